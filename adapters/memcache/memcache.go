@@ -0,0 +1,280 @@
+package memcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+	gomemcache "github.com/bradfitz/gomemcache/memcache"
+)
+
+const (
+	maxKeyLength    = 250
+	relativeTTLSpan = 30 * 24 * time.Hour
+)
+
+type memcacheCache[Key comparable, Val any] struct {
+	client *gomemcache.Client
+	ttl    time.Duration
+	prefix string
+	keys   cache.Serializer[Key]
+	vals   cache.Serializer[Val]
+	logger *slog.Logger
+	clock  cache.Clock
+	nsTTL  time.Duration
+	nsMu   sync.Mutex
+	ns     string
+	nsAt   time.Time
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+var _ cache.Cache[string, any] = (*memcacheCache[string, any])(nil)
+
+func New[Key comparable, Val any](client *gomemcache.Client, opts ...Option[Key, Val]) (*memcacheCache[Key, Val], error) {
+	if client == nil {
+		return nil, fmt.Errorf("%w: client must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &memcacheCache[Key, Val]{
+		client: client,
+		ttl:    o.ttl,
+		prefix: o.prefix,
+		keys:   o.keys,
+		vals:   o.vals,
+		logger: o.logger,
+		clock:  o.clock,
+		nsTTL:  o.nsTTL,
+	}, nil
+}
+
+func (c *memcacheCache[Key, Val]) Get(k Key) (Val, bool) {
+	var z Val
+	key, err := c.key(k)
+	if err != nil {
+		c.misses.Add(1)
+		return z, false
+	}
+	item, err := c.client.Get(key)
+	if err != nil {
+		if !errors.Is(err, gomemcache.ErrCacheMiss) {
+			c.logger.Debug("memcache get failed", "key", k, "err", err)
+		}
+		c.misses.Add(1)
+		return z, false
+	}
+	v, err := c.vals.Unmarshal(item.Value)
+	if err != nil {
+		c.misses.Add(1)
+		return z, false
+	}
+	c.hits.Add(1)
+	return v, true
+}
+
+func (c *memcacheCache[Key, Val]) GetMany(keys []Key) map[Key]Val {
+	found := make(map[Key]Val, len(keys))
+	names := make(map[string]Key, len(keys))
+	ns, err := c.namespace()
+	if err != nil {
+		c.logger.Debug("memcache namespace lookup failed", "err", err)
+		c.misses.Add(uint64(len(keys)))
+		return found
+	}
+	for _, k := range keys {
+		key, err := c.namespacedKey(ns, k)
+		if err != nil {
+			c.misses.Add(1)
+			continue
+		}
+		names[key] = k
+	}
+	if len(names) == 0 {
+		return found
+	}
+	list := make([]string, 0, len(names))
+	for key := range names {
+		list = append(list, key)
+	}
+	items, err := c.client.GetMulti(list)
+	if err != nil {
+		c.logger.Debug("memcache get many failed", "err", err)
+	}
+	for key, k := range names {
+		item, ok := items[key]
+		if !ok {
+			c.misses.Add(1)
+			continue
+		}
+		v, err := c.vals.Unmarshal(item.Value)
+		if err != nil {
+			c.misses.Add(1)
+			continue
+		}
+		c.hits.Add(1)
+		found[k] = v
+	}
+	return found
+}
+
+func (c *memcacheCache[Key, Val]) Put(k Key, v Val) {
+	c.PutWithTTL(k, v, c.ttl)
+}
+
+func (c *memcacheCache[Key, Val]) PutWithTTL(k Key, v Val, d time.Duration) {
+	if err := c.put(k, v, d); err != nil {
+		c.logger.Debug("memcache put failed", "key", k, "err", err)
+	}
+}
+
+func (c *memcacheCache[Key, Val]) put(k Key, v Val, ttl time.Duration) error {
+	key, err := c.key(k)
+	if err != nil {
+		return err
+	}
+	val, err := c.vals.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(&gomemcache.Item{Key: key, Value: val, Expiration: c.expiration(ttl)})
+}
+
+func (c *memcacheCache[Key, Val]) Delete(k Key) bool {
+	key, err := c.key(k)
+	if err != nil {
+		return false
+	}
+	err = c.client.Delete(key)
+	if err != nil && !errors.Is(err, gomemcache.ErrCacheMiss) {
+		c.logger.Debug("memcache delete failed", "key", k, "err", err)
+	}
+	return err == nil
+}
+
+func (c *memcacheCache[Key, Val]) Expire(k Key, d time.Duration) bool {
+	key, err := c.key(k)
+	if err != nil {
+		return false
+	}
+	err = c.client.Touch(key, c.expiration(d))
+	if err != nil && !errors.Is(err, gomemcache.ErrCacheMiss) {
+		c.logger.Debug("memcache touch failed", "key", k, "err", err)
+	}
+	return err == nil
+}
+
+// Len is always 0: memcached cannot enumerate or count keys under a prefix.
+func (c *memcacheCache[Key, Val]) Len() int {
+	return 0
+}
+
+func (c *memcacheCache[Key, Val]) Clear() {
+	gen, err := c.client.Increment(c.namespaceKey(), 1)
+	switch {
+	case err == nil:
+		c.setNamespace(strconv.FormatUint(gen, 10))
+	case errors.Is(err, gomemcache.ErrCacheMiss):
+		c.setNamespace("")
+		_, err = c.namespace()
+	}
+	if err != nil {
+		c.logger.Debug("memcache clear failed", "err", err)
+	}
+}
+
+func (c *memcacheCache[Key, Val]) Stats() cache.Stats {
+	return cache.Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+func (c *memcacheCache[Key, Val]) key(k Key) (string, error) {
+	ns, err := c.namespace()
+	if err != nil {
+		return "", err
+	}
+	return c.namespacedKey(ns, k)
+}
+
+func (c *memcacheCache[Key, Val]) namespacedKey(ns string, k Key) (string, error) {
+	b, err := c.keys.Marshal(k)
+	if err != nil {
+		return "", err
+	}
+	key := c.prefix + ns + ":" + string(b)
+	if !validKey(key) {
+		sum := sha256.Sum256(b)
+		key = c.prefix + ns + "#" + hex.EncodeToString(sum[:])
+	}
+	if !validKey(key) {
+		return "", gomemcache.ErrMalformedKey
+	}
+	return key, nil
+}
+
+func (c *memcacheCache[Key, Val]) namespaceKey() string {
+	return c.prefix + "#ns"
+}
+
+func (c *memcacheCache[Key, Val]) namespace() (string, error) {
+	c.nsMu.Lock()
+	ns, at := c.ns, c.nsAt
+	c.nsMu.Unlock()
+	if ns != "" && c.clock.Now().Sub(at) < c.nsTTL {
+		return ns, nil
+	}
+
+	item, err := c.client.Get(c.namespaceKey())
+	if errors.Is(err, gomemcache.ErrCacheMiss) {
+		seed := strconv.FormatInt(c.clock.Now().UnixNano(), 10)
+		err = c.client.Add(&gomemcache.Item{Key: c.namespaceKey(), Value: []byte(seed)})
+		if err != nil && !errors.Is(err, gomemcache.ErrNotStored) {
+			return "", err
+		}
+		item, err = c.client.Get(c.namespaceKey())
+	}
+	if err != nil {
+		return "", err
+	}
+	c.setNamespace(string(item.Value))
+	return string(item.Value), nil
+}
+
+func (c *memcacheCache[Key, Val]) setNamespace(ns string) {
+	c.nsMu.Lock()
+	defer c.nsMu.Unlock()
+	c.ns, c.nsAt = ns, c.clock.Now()
+}
+
+func (c *memcacheCache[Key, Val]) expiration(ttl time.Duration) int32 {
+	switch {
+	case ttl <= 0:
+		return 0
+	case ttl > relativeTTLSpan:
+		return int32(c.clock.Now().Add(ttl).Unix())
+	}
+	return int32((ttl + time.Second - 1) / time.Second)
+}
+
+func validKey(key string) bool {
+	if len(key) > maxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package memcache
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option[Key comparable, Val any] func(*options[Key, Val])
+
+type options[Key comparable, Val any] struct {
+	ttl    time.Duration
+	prefix string
+	codec  cache.Codec
+	keys   cache.Serializer[Key]
+	vals   cache.Serializer[Val]
+	logger *slog.Logger
+	clock  cache.Clock
+	nsTTL  time.Duration
+}
+
+func WithTTL[Key comparable, Val any](d time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.ttl = d
+	}
+}
+
+func WithPrefix[Key comparable, Val any](prefix string) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.prefix = prefix
+	}
+}
+
+func WithCodec[Key comparable, Val any](codec cache.Codec) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.codec = codec
+	}
+}
+
+func WithSerializer[Key comparable, Val any](ser cache.Serializer[Val]) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.vals = ser
+	}
+}
+
+func WithLogger[Key comparable, Val any](logger *slog.Logger) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.logger = logger
+	}
+}
+
+func WithClock[Key comparable, Val any](clock cache.Clock) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.clock = clock
+	}
+}
+
+func WithNamespaceRefresh[Key comparable, Val any](d time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.nsTTL = d
+	}
+}
+
+func applyOptions[Key comparable, Val any](opts []Option[Key, Val]) (options[Key, Val], error) {
+	o := options[Key, Val]{
+		codec:  cache.GobCodec,
+		logger: slog.New(slog.DiscardHandler),
+		clock:  cache.SystemClock,
+		nsTTL:  time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ttl < 0 {
+		return o, cache.ErrInvalidTTL
+	}
+	if o.codec == nil {
+		return o, fmt.Errorf("%w: codec must not be nil", cache.ErrInvalidOption)
+	}
+	if o.logger == nil {
+		return o, fmt.Errorf("%w: logger must not be nil", cache.ErrInvalidOption)
+	}
+	if o.clock == nil {
+		return o, fmt.Errorf("%w: clock must not be nil", cache.ErrInvalidOption)
+	}
+	if o.nsTTL < 0 {
+		return o, fmt.Errorf("%w: namespace refresh interval must not be negative", cache.ErrInvalidOption)
+	}
+	o.keys, _ = cache.NewSerializer[Key](cache.PlainCodec(o.codec))
+	if o.vals == nil {
+		o.vals, _ = cache.NewSerializer[Val](o.codec)
+	}
+	return o, nil
+}
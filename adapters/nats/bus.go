@@ -0,0 +1,43 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/assaidy/caches/cache"
+	gonats "github.com/nats-io/nats.go"
+)
+
+type invalidationBus struct {
+	conn    *gonats.Conn
+	subject string
+}
+
+var _ cache.InvalidationBus = (*invalidationBus)(nil)
+
+func NewInvalidationBus(conn *gonats.Conn, subject string) (*invalidationBus, error) {
+	if conn == nil {
+		return nil, fmt.Errorf("%w: connection must not be nil", cache.ErrInvalidOption)
+	}
+	if subject == "" {
+		return nil, fmt.Errorf("%w: subject must not be empty", cache.ErrInvalidOption)
+	}
+	return &invalidationBus{conn: conn, subject: subject}, nil
+}
+
+func (b *invalidationBus) Publish(ctx context.Context, msg []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return b.conn.Publish(b.subject, msg)
+}
+
+func (b *invalidationBus) Subscribe(fn func(msg []byte)) (func() error, error) {
+	sub, err := b.conn.Subscribe(b.subject, func(m *gonats.Msg) {
+		fn(m.Data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub.Drain, nil
+}
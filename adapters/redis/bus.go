@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/assaidy/caches/cache"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type invalidationBus struct {
+	client  goredis.UniversalClient
+	channel string
+}
+
+var _ cache.InvalidationBus = (*invalidationBus)(nil)
+
+func NewInvalidationBus(client goredis.UniversalClient, channel string) (*invalidationBus, error) {
+	if client == nil {
+		return nil, fmt.Errorf("%w: client must not be nil", cache.ErrInvalidOption)
+	}
+	if channel == "" {
+		return nil, fmt.Errorf("%w: channel must not be empty", cache.ErrInvalidOption)
+	}
+	return &invalidationBus{client: client, channel: channel}, nil
+}
+
+func (b *invalidationBus) Publish(ctx context.Context, msg []byte) error {
+	return b.client.Publish(ctx, b.channel, msg).Err()
+}
+
+func (b *invalidationBus) Subscribe(fn func(msg []byte)) (func() error, error) {
+	ctx := context.Background()
+	sub := b.client.Subscribe(ctx, b.channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for m := range sub.Channel() {
+			fn([]byte(m.Payload))
+		}
+	}()
+	return func() error {
+		err := sub.Close()
+		<-done
+		return err
+	}, nil
+}
@@ -0,0 +1,300 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const scanCount = 512
+
+type redisCache[Key comparable, Val any] struct {
+	client  goredis.UniversalClient
+	ttl     time.Duration
+	prefix  string
+	timeout time.Duration
+	keys    cache.Serializer[Key]
+	vals    cache.Serializer[Val]
+	logger  *slog.Logger
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+var _ cache.Cache[string, any] = (*redisCache[string, any])(nil)
+
+func New[Key comparable, Val any](client goredis.UniversalClient, opts ...Option[Key, Val]) (*redisCache[Key, Val], error) {
+	if client == nil {
+		return nil, fmt.Errorf("%w: client must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &redisCache[Key, Val]{
+		client:  client,
+		ttl:     o.ttl,
+		prefix:  o.prefix,
+		timeout: o.timeout,
+		keys:    o.keys,
+		vals:    o.vals,
+		logger:  o.logger,
+	}, nil
+}
+
+func (c *redisCache[Key, Val]) Get(k Key) (Val, bool) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	v, err := c.GetContext(ctx, k)
+	return v, err == nil
+}
+
+func (c *redisCache[Key, Val]) GetContext(ctx context.Context, k Key) (Val, error) {
+	var z Val
+	key, err := c.key(k)
+	if err != nil {
+		return z, err
+	}
+	raw, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		err = cache.ErrNotFound
+	}
+	if err != nil {
+		c.misses.Add(1)
+		return z, err
+	}
+	v, err := c.vals.Unmarshal(raw)
+	if err != nil {
+		c.misses.Add(1)
+		return z, err
+	}
+	c.hits.Add(1)
+	return v, nil
+}
+
+func (c *redisCache[Key, Val]) GetMany(keys []Key) map[Key]Val {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	found := make(map[Key]Val, len(keys))
+	names := make([]string, 0, len(keys))
+	wanted := make([]Key, 0, len(keys))
+	for _, k := range keys {
+		key, err := c.key(k)
+		if err != nil {
+			c.misses.Add(1)
+			continue
+		}
+		names = append(names, key)
+		wanted = append(wanted, k)
+	}
+	if len(names) == 0 {
+		return found
+	}
+	cmds := make([]*goredis.StringCmd, len(names))
+	_, err := c.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, key := range names {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		c.logger.Debug("redis cache get many failed", "err", err)
+	}
+	for i, cmd := range cmds {
+		raw, err := cmd.Bytes()
+		if err != nil {
+			c.misses.Add(1)
+			continue
+		}
+		v, err := c.vals.Unmarshal(raw)
+		if err != nil {
+			c.misses.Add(1)
+			continue
+		}
+		c.hits.Add(1)
+		found[wanted[i]] = v
+	}
+	return found
+}
+
+func (c *redisCache[Key, Val]) Put(k Key, v Val) {
+	c.PutWithTTL(k, v, c.ttl)
+}
+
+func (c *redisCache[Key, Val]) PutWithTTL(k Key, v Val, d time.Duration) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if err := c.put(ctx, k, v, d); err != nil {
+		c.logger.Debug("redis cache put failed", "key", k, "err", err)
+	}
+}
+
+func (c *redisCache[Key, Val]) put(ctx context.Context, k Key, v Val, ttl time.Duration) error {
+	key, err := c.key(k)
+	if err != nil {
+		return err
+	}
+	val, err := c.vals.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, key, val, ttl).Err()
+}
+
+func (c *redisCache[Key, Val]) Delete(k Key) bool {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	key, err := c.key(k)
+	if err != nil {
+		return false
+	}
+	n, err := c.client.Del(ctx, key).Result()
+	if err != nil {
+		c.logger.Debug("redis cache delete failed", "key", k, "err", err)
+		return false
+	}
+	return n > 0
+}
+
+func (c *redisCache[Key, Val]) Expire(k Key, d time.Duration) bool {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	key, err := c.key(k)
+	if err != nil {
+		return false
+	}
+	ok, err := c.client.Expire(ctx, key, d).Result()
+	if err != nil {
+		c.logger.Debug("redis cache expire failed", "key", k, "err", err)
+		return false
+	}
+	return ok
+}
+
+func (c *redisCache[Key, Val]) Persist(k Key) bool {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	key, err := c.key(k)
+	if err != nil {
+		return false
+	}
+	ok, err := c.client.Persist(ctx, key).Result()
+	if err != nil {
+		c.logger.Debug("redis cache persist failed", "key", k, "err", err)
+		return false
+	}
+	return ok
+}
+
+func (c *redisCache[Key, Val]) Len() int {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	var n atomic.Int64
+	err := c.forEachNode(ctx, func(ctx context.Context, node goredis.UniversalClient) error {
+		return c.scan(ctx, node, func(keys []string) error {
+			n.Add(int64(len(keys)))
+			return nil
+		})
+	})
+	if err != nil {
+		c.logger.Debug("redis cache len failed", "err", err)
+	}
+	return int(n.Load())
+}
+
+func (c *redisCache[Key, Val]) Clear() {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	err := c.forEachNode(ctx, func(ctx context.Context, node goredis.UniversalClient) error {
+		return c.scan(ctx, node, func(keys []string) error {
+			_, err := node.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+				for _, key := range keys {
+					pipe.Unlink(ctx, key)
+				}
+				return nil
+			})
+			return err
+		})
+	})
+	if err != nil {
+		c.logger.Debug("redis cache clear failed", "err", err)
+	}
+}
+
+func (c *redisCache[Key, Val]) Stats() cache.Stats {
+	return cache.Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Size:   c.Len(),
+	}
+}
+
+func (c *redisCache[Key, Val]) key(k Key) (string, error) {
+	b, err := c.keys.Marshal(k)
+	if err != nil {
+		return "", err
+	}
+	return c.prefix + string(b), nil
+}
+
+func (c *redisCache[Key, Val]) context() (context.Context, context.CancelFunc) {
+	if c.timeout > 0 {
+		return context.WithTimeout(context.Background(), c.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func (c *redisCache[Key, Val]) forEachNode(ctx context.Context, fn func(ctx context.Context, node goredis.UniversalClient) error) error {
+	if cluster, ok := c.client.(*goredis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *goredis.Client) error {
+			return fn(ctx, node)
+		})
+	}
+	return fn(ctx, c.client)
+}
+
+func (c *redisCache[Key, Val]) scan(ctx context.Context, node goredis.UniversalClient, fn func(keys []string) error) error {
+	iter := node.Scan(ctx, 0, escapePattern(c.prefix)+"*", scanCount).Iterator()
+	var batch []string
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == scanCount {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+func escapePattern(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '*', '?', '[', ']', '\\':
+			b = append(b, '\\')
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
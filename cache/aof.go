@@ -0,0 +1,263 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const minCompaction = 1024
+
+type logOp int
+
+const (
+	logPut logOp = iota + 1
+	logDelete
+	logClear
+)
+
+type logRecord[Key comparable, Val any] struct {
+	Op    logOp                   `json:"op"`
+	Entry snapshotEntry[Key, Val] `json:"entry"`
+}
+
+type appendLog[Key comparable, Val any] struct {
+	path      string
+	codec     Codec
+	logger    *slog.Logger
+	mu        sync.Mutex
+	file      *os.File
+	buf       *bufio.Writer
+	enc       Encoder
+	records   int
+	threshold int
+	compact   chan struct{}
+}
+
+func newAppendLog[Key comparable, Val any](path string, codec Codec, logger *slog.Logger) *appendLog[Key, Val] {
+	return &appendLog[Key, Val]{
+		path:    path,
+		codec:   codec,
+		logger:  logger,
+		compact: make(chan struct{}, 1),
+	}
+}
+
+func readAppendLog[Key comparable, Val any](path string, codec Codec, logger *slog.Logger) ([]logRecord[Key, Val], error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []logRecord[Key, Val]
+	dec := codec.NewDecoder(bufio.NewReader(f))
+	for {
+		var rec logRecord[Key, Val]
+		err := dec.Decode(&rec)
+		switch {
+		case err == nil:
+			records = append(records, rec)
+			continue
+		case errors.Is(err, io.EOF):
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			logger.Debug("cache append log truncated", "path", path, "records", len(records))
+		default:
+			return nil, err
+		}
+		return records, nil
+	}
+}
+
+func (l *appendLog[Key, Val]) append(op logOp, e *cacheEntry[Key, Val]) {
+	if l == nil {
+		return
+	}
+	rec := logRecord[Key, Val]{Op: op}
+	if e != nil {
+		rec.Entry = newSnapshotEntry(e)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := l.enc.Encode(rec)
+	if err == nil {
+		err = l.buf.Flush()
+	}
+	if err != nil {
+		l.logger.Debug("cache append log write failed", "path", l.path, "err", err)
+		return
+	}
+	l.records++
+	if l.records >= l.threshold {
+		select {
+		case l.compact <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (l *appendLog[Key, Val]) rewrite(entries []snapshotEntry[Key, Val]) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(f)
+	enc := l.codec.NewEncoder(buf)
+	for _, se := range entries {
+		if err = enc.Encode(logRecord[Key, Val]{Op: logPut, Entry: se}); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), l.path)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	if l.file != nil {
+		l.file.Close()
+	}
+	l.file, l.buf, l.enc = f, buf, enc
+	l.records = len(entries)
+	l.threshold = 2*len(entries) + minCompaction
+	l.logger.Debug("cache append log compacted", "path", l.path, "records", len(entries))
+	return nil
+}
+
+func (l *appendLog[Key, Val]) run(ctx context.Context, compact func() error, done chan struct{}) {
+	defer close(done)
+	for {
+		select {
+		case <-l.compact:
+			if err := compact(); err != nil {
+				l.logger.Debug("cache append log compaction failed", "path", l.path, "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (l *appendLog[Key, Val]) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.buf.Flush(); err != nil {
+		l.file.Close()
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
+
+func (c *lruCache[Key, Val]) openLog(path string) error {
+	records, err := readAppendLog[Key, Val](path, c.codec, c.logger)
+	if err != nil {
+		return err
+	}
+	c.replay(records)
+
+	c.aof = newAppendLog[Key, Val](path, c.codec, c.logger)
+	if err := c.compactLog(); err != nil {
+		return err
+	}
+	var ctx context.Context
+	ctx, c.stopLog = context.WithCancel(context.Background())
+	c.logDone = make(chan struct{})
+	go c.aof.run(ctx, c.compactLog, c.logDone)
+	return nil
+}
+
+func (c *lruCache[Key, Val]) replay(records []logRecord[Key, Val]) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	for _, rec := range records {
+		switch rec.Op {
+		case logPut:
+			c.restoreEntry(rec.Entry, now)
+		case logDelete:
+			if _, ok := c.store[rec.Entry.Key]; ok {
+				c.remove(rec.Entry.Key)
+			}
+		case logClear:
+			c.clear()
+		}
+	}
+}
+
+func (c *lruCache[Key, Val]) compactLog() error {
+	c.mu.Lock()
+	defer c.unlock()
+	return c.aof.rewrite(c.entries())
+}
+
+func (c *ttlCache[Key, Val]) openLog(path string) error {
+	records, err := readAppendLog[Key, Val](path, c.codec, c.logger)
+	if err != nil {
+		return err
+	}
+	c.replay(records)
+
+	c.aof = newAppendLog[Key, Val](path, c.codec, c.logger)
+	if err := c.compactLog(); err != nil {
+		return err
+	}
+	var ctx context.Context
+	ctx, c.stopLog = context.WithCancel(context.Background())
+	c.logDone = make(chan struct{})
+	go c.aof.run(ctx, c.compactLog, c.logDone)
+	return nil
+}
+
+func (c *ttlCache[Key, Val]) replay(records []logRecord[Key, Val]) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	for _, rec := range records {
+		switch rec.Op {
+		case logPut:
+			c.restoreEntry(rec.Entry, now)
+		case logDelete:
+			if e, ok := c.entry(rec.Entry.Key); ok {
+				c.remove(e)
+			}
+		case logClear:
+			c.clear()
+		}
+	}
+}
+
+func (c *ttlCache[Key, Val]) compactLog() error {
+	c.mu.Lock()
+	defer c.unlock()
+	return c.aof.rewrite(c.entries())
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const negativeCapacity = 1 << 14
+
+type aside[Key comparable, Val any] struct {
+	cache    Cache[Key, Val]
+	store    Store[Key, Val]
+	negative *ttlCache[Key, struct{}]
+	flights  flightGroup[Key, Val]
+}
+
+func NewAside[Key comparable, Val any](c Cache[Key, Val], store Store[Key, Val], negativeTTL time.Duration) (*aside[Key, Val], error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", ErrInvalidOption)
+	}
+	if store == nil {
+		return nil, fmt.Errorf("%w: store must not be nil", ErrInvalidOption)
+	}
+	if negativeTTL < 0 {
+		return nil, ErrInvalidTTL
+	}
+	a := &aside[Key, Val]{cache: c, store: store}
+	if negativeTTL > 0 {
+		neg, err := NewTTL(negativeTTL, false, WithCapacity[Key, struct{}](negativeCapacity))
+		if err != nil {
+			return nil, err
+		}
+		a.negative = neg
+	}
+	return a, nil
+}
+
+func (a *aside[Key, Val]) Get(ctx context.Context, k Key) (Val, error) {
+	if v, ok := a.cache.Get(k); ok {
+		return v, nil
+	}
+	if a.negative != nil {
+		if _, ok := a.negative.Get(k); ok {
+			var z Val
+			return z, ErrNotFound
+		}
+	}
+	return a.flights.do(ctx, k, func(ctx context.Context) (Val, error) {
+		v, err := a.store.Load(ctx, k)
+		if errors.Is(err, ErrNotFound) && a.negative != nil {
+			a.negative.Put(k, struct{}{})
+		}
+		if err != nil {
+			var z Val
+			return z, err
+		}
+		a.cache.Put(k, v)
+		return v, nil
+	})
+}
+
+func (a *aside[Key, Val]) Invalidate(k Key) {
+	a.cache.Delete(k)
+	if a.negative != nil {
+		a.negative.Delete(k)
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type snapshotter interface {
+	SaveTo(w io.Writer) error
+}
+
+func writeSnapshotFile(path string, s snapshotter) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if err := s.SaveTo(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func readSnapshotFile[Key comparable, Val any](path string, codec Codec) ([]snapshotEntry[Key, Val], error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	snap, err := decodeSnapshot[Key, Val](codec.NewDecoder(f))
+	return snap.Entries, err
+}
+
+func saveSnapshotFile(path string, s snapshotter, logger *slog.Logger) error {
+	err := writeSnapshotFile(path, s)
+	if err != nil {
+		logger.Debug("cache snapshot failed", "path", path, "err", err)
+	} else {
+		logger.Debug("cache snapshot saved", "path", path)
+	}
+	return err
+}
+
+func autoSnapshot(ctx context.Context, path string, interval time.Duration, s snapshotter, clock Clock, logger *slog.Logger, done chan struct{}) {
+	defer close(done)
+	ticker := clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			saveSnapshotFile(path, s, logger)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type BatchLoaderFunc[Key comparable, Val any] func(ctx context.Context, keys []Key) (map[Key]Val, error)
+
+type batchResult[Val any] struct {
+	val Val
+	err error
+}
+
+type batcher[Key comparable, Val any] struct {
+	fn      BatchLoaderFunc[Key, Val]
+	window  time.Duration
+	max     int
+	mu      sync.Mutex
+	pending map[Key][]chan batchResult[Val]
+	timer   *time.Timer
+}
+
+func newBatcher[Key comparable, Val any](fn BatchLoaderFunc[Key, Val], window time.Duration, max int) (*batcher[Key, Val], error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("%w: batch window must be greater than zero", ErrInvalidOption)
+	}
+	if max <= 0 {
+		return nil, fmt.Errorf("%w: batch size must be greater than zero", ErrInvalidOption)
+	}
+	return &batcher[Key, Val]{fn: fn, window: window, max: max}, nil
+}
+
+func (b *batcher[Key, Val]) load(ctx context.Context, k Key) (Val, error) {
+	ch := make(chan batchResult[Val], 1)
+
+	b.mu.Lock()
+	if b.pending == nil {
+		b.pending = make(map[Key][]chan batchResult[Val])
+		b.timer = time.AfterFunc(b.window, b.flush)
+	}
+	b.pending[k] = append(b.pending[k], ch)
+	full := len(b.pending) >= b.max
+	b.mu.Unlock()
+
+	if full {
+		b.flush()
+	}
+	select {
+	case r := <-ch:
+		return r.val, r.err
+	case <-ctx.Done():
+		var z Val
+		return z, ctx.Err()
+	}
+}
+
+func (b *batcher[Key, Val]) flush() {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	keys := make([]Key, 0, len(pending))
+	for k := range pending {
+		keys = append(keys, k)
+	}
+	found, err := b.fn(context.Background(), keys)
+	for k, waiters := range pending {
+		r := batchResult[Val]{err: err}
+		if err == nil {
+			v, ok := found[k]
+			r = batchResult[Val]{val: v}
+			if !ok {
+				r.err = ErrNotFound
+			}
+		}
+		for _, ch := range waiters {
+			ch <- r
+		}
+	}
+}
+
+func (c *lruCache[Key, Val]) LoadMany(ctx context.Context, keys []Key) (map[Key]Val, error) {
+	hits := c.GetMany(keys)
+	return loadMissing(ctx, hits, keys, c.GetContext)
+}
+
+func (c *ttlCache[Key, Val]) LoadMany(ctx context.Context, keys []Key) (map[Key]Val, error) {
+	hits := c.GetMany(keys)
+	return loadMissing(ctx, hits, keys, c.GetContext)
+}
+
+func loadMissing[Key comparable, Val any](ctx context.Context, found map[Key]Val, keys []Key, get func(context.Context, Key) (Val, error)) (map[Key]Val, error) {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, k := range keys {
+		if _, ok := found[k]; ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := get(ctx, k)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				found[k] = v
+			case !errors.Is(err, ErrNotFound):
+				errs = append(errs, fmt.Errorf("load %v: %w", k, err))
+			}
+		}()
+	}
+	wg.Wait()
+	return found, errors.Join(errs...)
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+type bloomFilter[Key comparable] struct {
+	bits   []uint64
+	hashes int
+	added  int
+	limit  int
+	seed   maphash.Seed
+}
+
+func newBloomFilter[Key comparable](expected int, fpRate float64) *bloomFilter[Key] {
+	m := int(math.Ceil(-float64(expected) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k := int(math.Round(float64(m) / float64(expected) * math.Ln2))
+	return &bloomFilter[Key]{
+		bits:   make([]uint64, (m+63)/64),
+		hashes: max(k, 1),
+		limit:  expected,
+		seed:   maphash.MakeSeed(),
+	}
+}
+
+func (f *bloomFilter[Key]) admit(k Key) bool {
+	h := maphash.Comparable(f.seed, k)
+	h1, h2 := h, h>>32|h<<32
+	m := uint64(len(f.bits) * 64)
+
+	seen := true
+	for i := 0; i < f.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			seen = false
+			f.bits[bit/64] |= 1 << (bit % 64)
+		}
+	}
+	if seen {
+		return true
+	}
+
+	f.added++
+	if f.added >= f.limit {
+		clear(f.bits)
+		f.added = 0
+	}
+	return false
+}
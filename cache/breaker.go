@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	breakerClosed = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type circuitBreaker struct {
+	threshold int
+	openFor   time.Duration
+	probes    int
+	clock     Clock
+	mu        sync.Mutex
+	state     int
+	failures  int
+	inflight  int
+	openedAt  time.Time
+}
+
+func newCircuitBreaker(threshold int, openFor time.Duration, probes int, clock Clock) (*circuitBreaker, error) {
+	if threshold <= 0 {
+		return nil, fmt.Errorf("%w: breaker threshold must be greater than zero", ErrInvalidOption)
+	}
+	if openFor <= 0 {
+		return nil, fmt.Errorf("%w: breaker open duration must be greater than zero", ErrInvalidOption)
+	}
+	if probes <= 0 {
+		probes = 1
+	}
+	return &circuitBreaker{threshold: threshold, openFor: openFor, probes: probes, clock: clock}, nil
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen {
+		if b.clock.Now().Sub(b.openedAt) < b.openFor {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.inflight = 0
+	}
+	if b.state == breakerHalfOpen {
+		if b.inflight >= b.probes {
+			return false
+		}
+		b.inflight++
+	}
+	return true
+}
+
+func (b *circuitBreaker) done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if errors.Is(err, context.Canceled) {
+		if b.state == breakerHalfOpen {
+			b.inflight--
+		}
+		return
+	}
+	failed := err != nil && !errors.Is(err, ErrNotFound)
+	if b.state == breakerHalfOpen {
+		b.inflight--
+		if failed {
+			b.trip()
+		} else {
+			b.state = breakerClosed
+			b.failures = 0
+		}
+		return
+	}
+	if !failed {
+		b.failures = 0
+		return
+	}
+	if b.failures++; b.failures >= b.threshold {
+		b.trip()
+	}
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = breakerOpen
+	b.openedAt = b.clock.Now()
+	b.failures = 0
+}
+
+func breakerLoader[Key comparable, Val any](b *circuitBreaker, fn LoaderFunc[Key, Val]) LoaderFunc[Key, Val] {
+	return func(ctx context.Context, k Key) (Val, error) {
+		if !b.allow() {
+			var z Val
+			return z, ErrCircuitOpen
+		}
+		v, err := fn(ctx, k)
+		b.done(err)
+		return v, err
+	}
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakerIgnoresCancellation(t *testing.T) {
+	clock := newFakeClock()
+	b, err := newCircuitBreaker(2, time.Second, 1, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failure := errors.New("backend down")
+
+	b.allow()
+	b.done(failure)
+	b.allow()
+	b.done(context.Canceled)
+	b.allow()
+	b.done(failure)
+	if b.allow() {
+		t.Fatal("breaker stayed closed after a cancellation hid a failure")
+	}
+
+	clock.Advance(time.Second)
+	if !b.allow() {
+		t.Fatal("breaker did not admit a half-open probe")
+	}
+	b.done(context.Canceled)
+	if b.state != breakerHalfOpen {
+		t.Fatalf("cancelled probe moved the breaker to state %d", b.state)
+	}
+	if !b.allow() {
+		t.Fatal("cancelled probe did not release its slot")
+	}
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+type EvictionReason int
+
+const (
+	EvictionCapacity EvictionReason = iota + 1
+	EvictionExpired
+)
+
+type evictedEntry[Key comparable, Val any] struct {
+	key       Key
+	val       Val
+	reason    EvictionReason
+	expiresAt time.Time
+}
+
+type Cache[Key comparable, Val any] interface {
+	Get(k Key) (Val, bool)
+	Put(k Key, v Val)
+	Delete(k Key) bool
+	Len() int
+	Clear()
+	Stats() Stats
+}
+
+var (
+	_ Cache[string, any] = (*lruCache[string, any])(nil)
+	_ Cache[string, any] = (*ttlCache[string, any])(nil)
+	_ Cache[string, any] = (*shardedCache[string, any])(nil)
+	_ Cache[string, any] = (*nopCache[string, any])(nil)
+	_ Cache[string, any] = (*tieredCache[string, any])(nil)
+	_ Cache[string, any] = (*writeThroughCache[string, any])(nil)
+	_ Cache[string, any] = (*writeBehindCache[string, any])(nil)
+	_ Cache[string, any] = (*invalidatingCache[string, any])(nil)
+)
+
+func New[Key comparable, Val any](opts ...Option[Key, Val]) (Cache[Key, Val], error) {
+	o := applyOptions(opts)
+	switch {
+	case o.ttl > 0:
+		c, err := newTTL(o)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case o.capacity > 0:
+		c, err := newLRU(o)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("%w: ttl or capacity must be greater than zero", ErrInvalidOption)
+}
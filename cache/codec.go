@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+type Encoder interface {
+	Encode(v any) error
+}
+
+type Decoder interface {
+	Decode(v any) error
+}
+
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+var (
+	GobCodec  Codec = gobCodec{}
+	JSONCodec Codec = jsonCodec{}
+)
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
+func (gobCodec) NewDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder { return json.NewEncoder(w) }
+func (jsonCodec) NewDecoder(r io.Reader) Decoder { return json.NewDecoder(r) }
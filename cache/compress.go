@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+const (
+	rawValue byte = iota
+	compressedValue
+)
+
+type Compressor interface {
+	Compress(src []byte) ([]byte, error)
+	Decompress(src []byte) ([]byte, error)
+}
+
+var GzipCompressor Compressor = gzipCompressor{gzip.DefaultCompression}
+
+type gzipCompressor struct {
+	level int
+}
+
+func NewGzipCompressor(level int) (Compressor, error) {
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		return nil, err
+	}
+	return gzipCompressor{level}, nil
+}
+
+func (g gzipCompressor) Compress(src []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, g.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCompressor) Decompress(src []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func WithCompression[Key comparable](c Compressor, minSize int) Option[Key, []byte] {
+	return func(o *options[Key, []byte]) {
+		o.pack = func(v []byte) []byte {
+			if len(v) >= minSize {
+				if out, err := c.Compress(v); err == nil && len(out) < len(v) {
+					return append([]byte{compressedValue}, out...)
+				}
+			}
+			return append([]byte{rawValue}, v...)
+		}
+		o.unpack = func(v []byte) ([]byte, error) {
+			if len(v) == 0 || v[0] == rawValue {
+				return v[min(len(v), 1):], nil
+			}
+			return c.Decompress(v[1:])
+		}
+	}
+}
+
+func (c *lruCache[Key, Val]) pack(v Val) Val {
+	if c.packer == nil {
+		return v
+	}
+	return c.packer(v)
+}
+
+func (c *lruCache[Key, Val]) value(e *cacheEntry[Key, Val]) Val {
+	v, _ := c.unpacked(e)
+	return v
+}
+
+func (c *lruCache[Key, Val]) unpacked(e *cacheEntry[Key, Val]) (Val, error) {
+	if c.unpacker == nil {
+		return e.value(), nil
+	}
+	return c.unpacker(e.value())
+}
+
+func (c *lruCache[Key, Val]) checked(e *cacheEntry[Key, Val]) (Val, bool) {
+	v, err := c.unpacked(e)
+	if err != nil {
+		c.mu.Lock()
+		c.corrupt(e, err)
+		c.unlock()
+		return v, false
+	}
+	return v, true
+}
+
+func (c *lruCache[Key, Val]) corrupt(e *cacheEntry[Key, Val], err error) {
+	c.logger.Warn("cache value could not be unpacked", "key", e.key, "err", err)
+	if c.store[e.key] == e {
+		c.drop(e.key)
+	}
+}
+
+func (c *ttlCache[Key, Val]) pack(v Val) Val {
+	if c.packer == nil {
+		return v
+	}
+	return c.packer(v)
+}
+
+func (c *ttlCache[Key, Val]) value(e *cacheEntry[Key, Val]) Val {
+	v, _ := c.unpacked(e)
+	return v
+}
+
+func (c *ttlCache[Key, Val]) unpacked(e *cacheEntry[Key, Val]) (Val, error) {
+	if c.unpacker == nil {
+		return e.value(), nil
+	}
+	return c.unpacker(e.value())
+}
+
+func (c *ttlCache[Key, Val]) checked(e *cacheEntry[Key, Val]) (Val, bool) {
+	v, err := c.unpacked(e)
+	if err != nil {
+		c.mu.Lock()
+		c.corrupt(e, err)
+		c.unlock()
+		return v, false
+	}
+	return v, true
+}
+
+func (c *ttlCache[Key, Val]) corrupt(e *cacheEntry[Key, Val], err error) {
+	c.logger.Warn("cache value could not be unpacked", "key", e.key, "err", err)
+	if cur, ok := c.entry(e.key); ok && cur == e {
+		c.drop(e)
+	}
+}
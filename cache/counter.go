@@ -0,0 +1,24 @@
+package cache
+
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+type updater[Key comparable, Val any] interface {
+	Update(k Key, fn func(old Val, exists bool) (Val, bool))
+}
+
+func IncrBy[Key comparable, Val Number](c updater[Key, Val], k Key, delta Val) Val {
+	var n Val
+	c.Update(k, func(old Val, _ bool) (Val, bool) {
+		n = old + delta
+		return n, true
+	})
+	return n
+}
+
+func DecrBy[Key comparable, Val Number](c updater[Key, Val], k Key, delta Val) Val {
+	return IncrBy(c, k, -delta)
+}
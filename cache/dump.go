@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+type dumpRow struct {
+	key    any
+	val    any
+	age    time.Duration
+	expiry time.Time
+	hits   uint64
+}
+
+func newDumpRow[Key comparable, Val any](e *cacheEntry[Key, Val], now time.Time) dumpRow {
+	return dumpRow{
+		key:    e.key,
+		val:    e.value(),
+		age:    now.Sub(time.Unix(0, e.created)),
+		expiry: e.expiresAt(),
+		hits:   e.hits.Load(),
+	}
+}
+
+func writeDump(w io.Writer, rows []dumpRow, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tTYPE\tAGE\tTTL\tHITS")
+	for _, r := range rows {
+		ttl := "-"
+		if !r.expiry.IsZero() {
+			ttl = r.expiry.Sub(now).Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(tw, "%v\t%T\t%s\t%s\t%d\n", r.key, r.val, r.age.Round(time.Millisecond), ttl, r.hits)
+	}
+	return tw.Flush()
+}
+
+func (c *lruCache[Key, Val]) Dump(w io.Writer, limit int) error {
+	c.mu.RLock()
+	now := c.clock.Now()
+	rows := make([]dumpRow, 0, len(c.store))
+	it := c.order.Iterator()
+	for it.End(); it.Prev() && (limit <= 0 || len(rows) < limit); {
+		if e := c.store[it.Value().(Key)]; !c.stale(e) {
+			rows = append(rows, newDumpRow(e, now))
+		}
+	}
+	c.mu.RUnlock()
+
+	return writeDump(w, rows, now)
+}
+
+func (c *ttlCache[Key, Val]) Dump(w io.Writer, limit int) error {
+	now := c.clock.Now()
+	var rows []dumpRow
+	c.store.Range(func(_, v any) bool {
+		if e := v.(*cacheEntry[Key, Val]); !c.expired(e) {
+			rows = append(rows, newDumpRow(e, now))
+		}
+		return limit <= 0 || len(rows) < limit
+	})
+	return writeDump(w, rows, now)
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const maxFrameSize = 1 << 30
+
+type encryptedCodec struct {
+	inner Codec
+	key   []byte
+}
+
+func NewEncryptedCodec(inner Codec, key []byte) (Codec, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("%w: codec must not be nil", ErrInvalidOption)
+	}
+	if _, err := aes.NewCipher(key); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOption, err)
+	}
+	return encryptedCodec{inner, key}, nil
+}
+
+func (c encryptedCodec) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (c encryptedCodec) NewEncoder(w io.Writer) Encoder {
+	aead, err := c.aead()
+	e := &sealingEncoder{w: w, aead: aead, err: err}
+	e.enc = c.inner.NewEncoder(&e.buf)
+	return e
+}
+
+func (c encryptedCodec) NewDecoder(r io.Reader) Decoder {
+	aead, err := c.aead()
+	return c.inner.NewDecoder(&openingReader{r: r, aead: aead, err: err})
+}
+
+type sealingEncoder struct {
+	w    io.Writer
+	aead cipher.AEAD
+	err  error
+	buf  bytes.Buffer
+	enc  Encoder
+}
+
+func (e *sealingEncoder) Encode(v any) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.buf.Reset()
+	if err := e.enc.Encode(v); err != nil {
+		return err
+	}
+
+	ns := e.aead.NonceSize()
+	frame := make([]byte, 4+ns, 4+ns+e.buf.Len()+e.aead.Overhead())
+	if _, err := rand.Read(frame[4:]); err != nil {
+		return err
+	}
+	frame = e.aead.Seal(frame, frame[4:], e.buf.Bytes(), nil)
+	binary.BigEndian.PutUint32(frame, uint32(len(frame)-4))
+	_, err := e.w.Write(frame)
+	return err
+}
+
+type openingReader struct {
+	r    io.Reader
+	aead cipher.AEAD
+	err  error
+	buf  []byte
+}
+
+func (o *openingReader) Read(p []byte) (int, error) {
+	for len(o.buf) == 0 {
+		if o.err != nil {
+			return 0, o.err
+		}
+		o.buf, o.err = o.next()
+	}
+	n := copy(p, o.buf)
+	o.buf = o.buf[n:]
+	return n, nil
+}
+
+func (o *openingReader) next() ([]byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(o.r, hdr[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(hdr[:])
+	if size > maxFrameSize || int(size) < o.aead.NonceSize() {
+		return nil, fmt.Errorf("bad encrypted frame size %d", size)
+	}
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(o.r, frame); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	ns := o.aead.NonceSize()
+	return o.aead.Open(nil, frame[:ns], frame[ns:], nil)
+}
+
+func WithEncryption[Key comparable, Val any](key []byte) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.encryptionKey = key
+	}
+}
+
+func PlainCodec(c Codec) Codec {
+	if e, ok := c.(encryptedCodec); ok {
+		return e.inner
+	}
+	return c
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type cacheEntry[Key comparable, Val any] struct {
+	key         Key
+	val         atomic.Pointer[Val]
+	ttl         atomic.Int64
+	lastVisited atomic.Int64
+	lastAccess  atomic.Int64
+	hits        atomic.Uint64
+	cost        atomic.Int64
+	written     atomic.Int64
+	pinned      atomic.Bool
+	refreshing  atomic.Bool
+	created     int64
+	weight      int64
+	deadline    int64
+	expiresBy   int64
+	maxIdle     int64
+	gen         uint64
+	index       int
+	priority    Priority
+	inline      Val
+}
+
+func newCacheEntry[Key comparable, Val any](k Key, v Val, ttl time.Duration, now time.Time) *cacheEntry[Key, Val] {
+	e := &cacheEntry[Key, Val]{key: k, index: -1, inline: v, created: now.UnixNano()}
+	e.val.Store(&e.inline)
+	e.ttl.Store(int64(ttl))
+	e.lastVisited.Store(now.UnixNano())
+	e.written.Store(now.UnixNano())
+	return e
+}
+
+func (e *cacheEntry[Key, Val]) value() Val {
+	return *e.val.Load()
+}
+
+func (e *cacheEntry[Key, Val]) setValue(v Val) {
+	e.val.Store(&v)
+}
+
+func (e *cacheEntry[Key, Val]) timeToLive() time.Duration {
+	return time.Duration(e.ttl.Load())
+}
+
+func (e *cacheEntry[Key, Val]) setTimeToLive(d time.Duration) {
+	e.ttl.Store(int64(d))
+}
+
+func (e *cacheEntry[Key, Val]) visit(now time.Time) {
+	e.lastVisited.Store(now.UnixNano())
+}
+
+func (e *cacheEntry[Key, Val]) access(now time.Time) {
+	e.hits.Add(1)
+	e.lastAccess.Store(now.UnixNano())
+}
+
+func (e *cacheEntry[Key, Val]) expiresAt() time.Time {
+	ttl := e.ttl.Load()
+	if ttl == 0 {
+		return time.Time{}
+	}
+	exp := e.lastVisited.Load() + ttl
+	if e.expiresBy != 0 && e.expiresBy < exp {
+		exp = e.expiresBy
+	}
+	if e.maxIdle != 0 {
+		if idle := max(e.lastAccess.Load(), e.written.Load()) + e.maxIdle; idle < exp {
+			exp = idle
+		}
+	}
+	return time.Unix(0, exp)
+}
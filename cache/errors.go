@@ -0,0 +1,15 @@
+package cache
+
+import "errors"
+
+var (
+	ErrNotFound        = errors.New("key not found")
+	ErrInvalidTTL      = errors.New("ttl must be greater than zero")
+	ErrInvalidCapacity = errors.New("capacity must be greater than zero")
+	ErrInvalidOption   = errors.New("invalid option")
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+	ErrNoLoader        = errors.New("no loader configured")
+	ErrQueueFull       = errors.New("write queue full")
+	ErrCircuitOpen     = errors.New("loader circuit open")
+	ErrRateLimited     = errors.New("loader rate limit exceeded")
+)
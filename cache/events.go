@@ -0,0 +1,24 @@
+package cache
+
+type EventType int
+
+const (
+	EventPut EventType = iota + 1
+	EventDelete
+	EventEvict
+	EventExpire
+	EventClear
+)
+
+type Event[Key comparable, Val any] struct {
+	Type EventType
+	Key  Key
+	Val  Val
+}
+
+func evictionEvent(reason EvictionReason) EventType {
+	if reason == EvictionExpired {
+		return EventExpire
+	}
+	return EventEvict
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"container/heap"
+	"time"
+)
+
+type expiryQueue[Key comparable, Val any] interface {
+	schedule(e *cacheEntry[Key, Val])
+	remove(e *cacheEntry[Key, Val])
+	expire(now time.Time, limit int) []*cacheEntry[Key, Val]
+	peek() *cacheEntry[Key, Val]
+	clear()
+}
+
+type expiryHeap[Key comparable, Val any] []*cacheEntry[Key, Val]
+
+func (h expiryHeap[Key, Val]) Len() int { return len(h) }
+
+func (h expiryHeap[Key, Val]) Less(i, j int) bool {
+	return h[i].deadline < h[j].deadline
+}
+
+func (h expiryHeap[Key, Val]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap[Key, Val]) Push(x any) {
+	e := x.(*cacheEntry[Key, Val])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap[Key, Val]) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
+
+func (h *expiryHeap[Key, Val]) schedule(e *cacheEntry[Key, Val]) {
+	ttl := e.timeToLive()
+	e.deadline = e.expiresAt().UnixNano()
+	switch {
+	case ttl == 0 && e.index >= 0:
+		heap.Remove(h, e.index)
+	case ttl == 0:
+	case e.index >= 0:
+		heap.Fix(h, e.index)
+	default:
+		heap.Push(h, e)
+	}
+}
+
+func (h *expiryHeap[Key, Val]) remove(e *cacheEntry[Key, Val]) {
+	if e.index >= 0 {
+		heap.Remove(h, e.index)
+	}
+}
+
+func (h *expiryHeap[Key, Val]) expire(now time.Time, limit int) []*cacheEntry[Key, Val] {
+	var due []*cacheEntry[Key, Val]
+	for len(*h) > 0 && (limit <= 0 || len(due) < limit) && (*h)[0].deadline <= now.UnixNano() {
+		e := (*h)[0]
+		if now.Before(e.expiresAt()) {
+			h.schedule(e)
+			continue
+		}
+		heap.Pop(h)
+		due = append(due, e)
+	}
+	return due
+}
+
+func (h *expiryHeap[Key, Val]) peek() *cacheEntry[Key, Val] {
+	if len(*h) == 0 {
+		return nil
+	}
+	return (*h)[0]
+}
+
+func (h *expiryHeap[Key, Val]) clear() {
+	*h = nil
+}
@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"expvar"
+	"fmt"
+)
+
+type statser interface {
+	Stats() Stats
+}
+
+func PublishExpvar(name string, c statser) error {
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("%w: expvar %q is already published", ErrInvalidOption, name)
+	}
+	expvar.Publish(name, expvar.Func(func() any { return c.Stats() }))
+	return nil
+}
@@ -0,0 +1,47 @@
+package cache
+
+func (c *lruCache[Key, Val]) Generation() uint64 {
+	return c.generation.Load()
+}
+
+func (c *lruCache[Key, Val]) InvalidateAll() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.generation.Add(1)
+	c.staleCount = len(c.store)
+	c.aof.append(logClear, nil)
+	if c.spill != nil {
+		c.spill.Clear()
+	}
+}
+
+func (c *lruCache[Key, Val]) stale(e *cacheEntry[Key, Val]) bool {
+	return e.gen != c.generation.Load()
+}
+
+func (c *lruCache[Key, Val]) live(k Key) (*cacheEntry[Key, Val], bool) {
+	e, ok := c.store[k]
+	if ok && c.stale(e) {
+		c.notify(e, EvictionExpired)
+		c.remove(k)
+		return nil, false
+	}
+	return e, ok
+}
+
+func (c *ttlCache[Key, Val]) Generation() uint64 {
+	return c.generation.Load()
+}
+
+func (c *ttlCache[Key, Val]) InvalidateAll() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.generation.Add(1)
+	c.staleCount = c.size
+	c.aof.append(logClear, nil)
+	if c.spill != nil {
+		c.spill.Clear()
+	}
+}
@@ -0,0 +1,46 @@
+package cache
+
+import "time"
+
+type EntryInfo struct {
+	Created    time.Time
+	LastAccess time.Time
+	Hits       uint64
+	TTL        time.Duration
+	Weight     int64
+}
+
+func newEntryInfo[Key comparable, Val any](e *cacheEntry[Key, Val], now time.Time) EntryInfo {
+	info := EntryInfo{
+		Created: time.Unix(0, e.created),
+		Hits:    e.hits.Load(),
+		Weight:  e.weight,
+	}
+	if t := e.lastAccess.Load(); t != 0 {
+		info.LastAccess = time.Unix(0, t)
+	}
+	if exp := e.expiresAt(); !exp.IsZero() {
+		info.TTL = exp.Sub(now)
+	}
+	return info
+}
+
+func (c *lruCache[Key, Val]) EntryInfo(k Key) (EntryInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if e, ok := c.store[k]; ok && !c.stale(e) {
+		return newEntryInfo(e, c.clock.Now()), true
+	}
+	return EntryInfo{}, false
+}
+
+func (c *ttlCache[Key, Val]) EntryInfo(k Key) (EntryInfo, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && !c.expired(e) {
+		return newEntryInfo(e, c.clock.Now()), true
+	}
+	return EntryInfo{}, false
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+)
+
+type InvalidationBus interface {
+	Publish(ctx context.Context, msg []byte) error
+	Subscribe(fn func(msg []byte)) (unsubscribe func() error, err error)
+}
+
+type InvalidationOption[Key comparable] func(*invalidationOptions[Key])
+
+type invalidationOptions[Key comparable] struct {
+	keys   Serializer[Key]
+	logger *slog.Logger
+}
+
+func WithInvalidationKeys[Key comparable](ser Serializer[Key]) InvalidationOption[Key] {
+	return func(o *invalidationOptions[Key]) {
+		o.keys = ser
+	}
+}
+
+func WithInvalidationLogger[Key comparable](logger *slog.Logger) InvalidationOption[Key] {
+	return func(o *invalidationOptions[Key]) {
+		o.logger = logger
+	}
+}
+
+const (
+	invalidateKey byte = iota + 1
+	invalidateAll
+)
+
+const originSize = 16
+
+type invalidatingCache[Key comparable, Val any] struct {
+	Cache[Key, Val]
+	bus         InvalidationBus
+	keys        Serializer[Key]
+	logger      *slog.Logger
+	origin      [originSize]byte
+	unsubscribe func() error
+}
+
+func NewInvalidating[Key comparable, Val any](c Cache[Key, Val], bus InvalidationBus, opts ...InvalidationOption[Key]) (*invalidatingCache[Key, Val], error) {
+	o := invalidationOptions[Key]{
+		logger: slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", ErrInvalidOption)
+	}
+	if bus == nil {
+		return nil, fmt.Errorf("%w: invalidation bus must not be nil", ErrInvalidOption)
+	}
+	if o.logger == nil {
+		return nil, fmt.Errorf("%w: logger must not be nil", ErrInvalidOption)
+	}
+	if o.keys == nil {
+		o.keys, _ = NewSerializer[Key](GobCodec)
+	}
+	ic := &invalidatingCache[Key, Val]{
+		Cache:  c,
+		bus:    bus,
+		keys:   o.keys,
+		logger: o.logger,
+	}
+	rand.Read(ic.origin[:])
+	unsubscribe, err := bus.Subscribe(ic.receive)
+	if err != nil {
+		return nil, err
+	}
+	ic.unsubscribe = unsubscribe
+	return ic, nil
+}
+
+func (c *invalidatingCache[Key, Val]) Put(k Key, v Val) {
+	c.Cache.Put(k, v)
+	c.publishKey(k)
+}
+
+func (c *invalidatingCache[Key, Val]) Delete(k Key) bool {
+	deleted := c.Cache.Delete(k)
+	c.publishKey(k)
+	return deleted
+}
+
+func (c *invalidatingCache[Key, Val]) Clear() {
+	c.Cache.Clear()
+	c.publish(invalidateAll, nil)
+}
+
+func (c *invalidatingCache[Key, Val]) Close() error {
+	return c.unsubscribe()
+}
+
+func (c *invalidatingCache[Key, Val]) publishKey(k Key) {
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		c.logger.Debug("invalidation key encoding failed", "key", k, "err", err)
+		return
+	}
+	c.publish(invalidateKey, key)
+}
+
+func (c *invalidatingCache[Key, Val]) publish(op byte, key []byte) {
+	msg := make([]byte, 0, 1+originSize+len(key))
+	msg = append(msg, op)
+	msg = append(msg, c.origin[:]...)
+	msg = append(msg, key...)
+	if err := c.bus.Publish(context.Background(), msg); err != nil {
+		c.logger.Debug("invalidation publish failed", "err", err)
+	}
+}
+
+func (c *invalidatingCache[Key, Val]) receive(msg []byte) {
+	if len(msg) < 1+originSize || [originSize]byte(msg[1:1+originSize]) == c.origin {
+		return
+	}
+	switch msg[0] {
+	case invalidateKey:
+		k, err := c.keys.Unmarshal(msg[1+originSize:])
+		if err != nil {
+			c.logger.Debug("invalidation key decoding failed", "err", err)
+			return
+		}
+		c.Cache.Delete(k)
+	case invalidateAll:
+		c.Cache.Clear()
+	}
+}
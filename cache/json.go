@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func exportJSON[Key comparable, Val any](w io.Writer, snap snapshot[Key, Val]) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snap)
+}
+
+func (c *lruCache[Key, Val]) ExportJSON(w io.Writer) error {
+	return exportJSON(w, c.snapshot())
+}
+
+func (c *lruCache[Key, Val]) ImportJSON(r io.Reader) error {
+	snap, err := decodeSnapshot[Key, Val](json.NewDecoder(r))
+	if err != nil {
+		return err
+	}
+	c.restore(snap.Entries)
+	return nil
+}
+
+func (c *ttlCache[Key, Val]) ExportJSON(w io.Writer) error {
+	return exportJSON(w, c.snapshot())
+}
+
+func (c *ttlCache[Key, Val]) ImportJSON(r io.Reader) error {
+	snap, err := decodeSnapshot[Key, Val](json.NewDecoder(r))
+	if err != nil {
+		return err
+	}
+	c.restore(snap.Entries)
+	return nil
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"hash/maphash"
+	"slices"
+	"sync"
+)
+
+const lockStripes = 256
+
+type stripedLocks[Key comparable] struct {
+	once    sync.Once
+	seed    maphash.Seed
+	stripes [lockStripes]sync.Mutex
+}
+
+func (l *stripedLocks[Key]) stripe(k Key) int {
+	l.once.Do(func() { l.seed = maphash.MakeSeed() })
+	return int(maphash.Comparable(l.seed, k) % lockStripes)
+}
+
+func (l *stripedLocks[Key]) lock(k Key) func() {
+	mu := &l.stripes[l.stripe(k)]
+	mu.Lock()
+	var once sync.Once
+	return func() { once.Do(mu.Unlock) }
+}
+
+func (l *stripedLocks[Key]) lockAll(keys []Key) func() {
+	idx := make([]int, 0, len(keys))
+	for _, k := range keys {
+		idx = append(idx, l.stripe(k))
+	}
+	slices.Sort(idx)
+	idx = slices.Compact(idx)
+	for _, i := range idx {
+		l.stripes[i].Lock()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for _, i := range slices.Backward(idx) {
+				l.stripes[i].Unlock()
+			}
+		})
+	}
+}
+
+func (c *lruCache[Key, Val]) LockKey(k Key) (unlock func()) {
+	return c.keyLocks.lock(k)
+}
+
+func (c *lruCache[Key, Val]) Do(k Key, fn func()) {
+	unlock := c.keyLocks.lock(k)
+	defer unlock()
+	fn()
+}
+
+func (c *lruCache[Key, Val]) LockKeys(keys ...Key) (unlock func()) {
+	return c.keyLocks.lockAll(keys)
+}
+
+func (c *ttlCache[Key, Val]) LockKey(k Key) (unlock func()) {
+	return c.keyLocks.lock(k)
+}
+
+func (c *ttlCache[Key, Val]) Do(k Key, fn func()) {
+	unlock := c.keyLocks.lock(k)
+	defer unlock()
+	fn()
+}
+
+func (c *ttlCache[Key, Val]) LockKeys(keys ...Key) (unlock func()) {
+	return c.keyLocks.lockAll(keys)
+}
@@ -0,0 +1,56 @@
+package cache
+
+import "iter"
+
+func (c *lruCache[Key, Val]) Keys() []Key {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]Key, 0, len(c.store))
+	it := c.order.Iterator()
+	for it.End(); it.Prev(); {
+		if k := it.Value().(Key); !c.stale(c.store[k]) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func (c *lruCache[Key, Val]) All() iter.Seq2[Key, Val] {
+	return func(yield func(Key, Val) bool) {
+		c.mu.RLock()
+		entries := make([]*cacheEntry[Key, Val], 0, len(c.store))
+		for _, e := range c.store {
+			if !c.stale(e) {
+				entries = append(entries, e)
+			}
+		}
+		c.mu.RUnlock()
+
+		for _, e := range entries {
+			if !yield(e.key, c.value(e)) {
+				return
+			}
+		}
+	}
+}
+
+func (c *ttlCache[Key, Val]) Keys() []Key {
+	var keys []Key
+	c.store.Range(func(_, v any) bool {
+		if e := v.(*cacheEntry[Key, Val]); !c.expired(e) {
+			keys = append(keys, e.key)
+		}
+		return true
+	})
+	return keys
+}
+
+func (c *ttlCache[Key, Val]) All() iter.Seq2[Key, Val] {
+	return func(yield func(Key, Val) bool) {
+		c.store.Range(func(_, v any) bool {
+			e := v.(*cacheEntry[Key, Val])
+			return c.expired(e) || yield(e.key, c.value(e))
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package cache
+
+import "sync"
+
+type locker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+type noLock struct{}
+
+func (noLock) Lock()    {}
+func (noLock) Unlock()  {}
+func (noLock) RLock()   {}
+func (noLock) RUnlock() {}
+
+func newLocker(disabled bool) locker {
+	if disabled {
+		return noLock{}
+	}
+	return &sync.RWMutex{}
+}
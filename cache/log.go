@@ -0,0 +1,11 @@
+package cache
+
+import "log/slog"
+
+const evictionStorm = 64
+
+func logEvictions(logger *slog.Logger, n int) {
+	if n >= evictionStorm {
+		logger.Debug("cache eviction storm", "evicted", n)
+	}
+}
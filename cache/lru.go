@@ -1,35 +1,262 @@
 package cache
 
 import (
+	"context"
+	"crypto/aes"
+	"errors"
 	"fmt"
 	dll "github.com/emirpasic/gods/lists/doublylinkedlist"
-	"sync"
+	"log/slog"
+	"sync/atomic"
+	"time"
 )
 
 type lruCache[Key comparable, Val any] struct {
-	capacity int
-	store    map[Key]Val
-	order    *dll.List
-	mu       sync.Mutex
+	capacity     int
+	store        map[Key]*cacheEntry[Key, Val]
+	order        *dll.List
+	weigher      func(k Key, v Val) int64
+	weight       int64
+	maxWeight    int64
+	sizer        func(k Key, v Val) int64
+	doorkeeper   *bloomFilter[Key]
+	clock        Clock
+	logger       *slog.Logger
+	codec        Codec
+	loader       LoaderFunc[Key, Val]
+	refreshAfter time.Duration
+	refreshes    flightGroup[Key, Val]
+	negative     *negativeCache[Key]
+	warmWorkers  int
+	equal        func(a, b Val) bool
+	onEvict      func(k Key, v Val, reason EvictionReason)
+	spill        SpillStore[Key, Val]
+	packer       func(v Val) Val
+	unpacker     func(v Val) (Val, error)
+	evicted      []evictedEntry[Key, Val]
+	events       chan Event[Key, Val]
+	flights      flightGroup[Key, Val]
+	tags         tagIndex[Key]
+	prefixes     *prefixIndex
+	priorities   priorityLevels
+	keyLocks     stripedLocks[Key]
+	generation   atomic.Uint64
+	staleCount   int
+	stats        *statsCounter
+	accesses     chan Key
+	stop         chan struct{}
+	drained      chan struct{}
+	closed       atomic.Bool
+	snapshotPath string
+	stopSnapshot context.CancelFunc
+	snapshotDone chan struct{}
+	aof          *appendLog[Key, Val]
+	stopLog      context.CancelFunc
+	logDone      chan struct{}
+	mu           locker
 }
 
-func NewLRU[Key comparable, Val any](cap int) (*lruCache[Key, Val], error) {
-	if cap <= 0 {
-		return nil, fmt.Errorf("capacity must be greater than zero")
+func NewLRU[Key comparable, Val any](cap int, opts ...Option[Key, Val]) (*lruCache[Key, Val], error) {
+	o := applyOptions(opts)
+	o.capacity = cap
+	return newLRU(o)
+}
+
+func newLRU[Key comparable, Val any](o options[Key, Val]) (*lruCache[Key, Val], error) {
+	if o.capacity <= 0 {
+		return nil, ErrInvalidCapacity
+	}
+	if o.accessBuf < 0 {
+		return nil, fmt.Errorf("%w: access buffer size must be greater than zero", ErrInvalidOption)
+	}
+	if o.noLocking && o.accessBuf > 0 {
+		return nil, fmt.Errorf("%w: access buffer requires locking", ErrInvalidOption)
+	}
+	if o.maxWeight < 0 {
+		return nil, fmt.Errorf("%w: max weight must be greater than zero", ErrInvalidOption)
+	}
+	if o.doorkeeper < 0 {
+		return nil, fmt.Errorf("%w: doorkeeper size must be greater than zero", ErrInvalidOption)
+	}
+	if o.eventBuf < 0 {
+		return nil, fmt.Errorf("%w: event buffer size must be greater than zero", ErrInvalidOption)
+	}
+	if o.warmWorkers < 0 {
+		return nil, fmt.Errorf("%w: warm concurrency must be greater than zero", ErrInvalidOption)
+	}
+	if o.noLocking {
+		o.warmWorkers = 1
+	}
+	if o.clock == nil {
+		return nil, fmt.Errorf("%w: clock must not be nil", ErrInvalidOption)
+	}
+	if err := o.wrapLoader(); err != nil {
+		return nil, err
+	}
+	if o.refreshAfter < 0 {
+		return nil, fmt.Errorf("%w: refresh interval must be greater than zero", ErrInvalidOption)
+	}
+	if o.refreshAfter > 0 && o.loader == nil {
+		return nil, fmt.Errorf("%w: refresh-ahead requires a loader", ErrInvalidOption)
+	}
+	if o.logger == nil {
+		return nil, fmt.Errorf("%w: logger must not be nil", ErrInvalidOption)
+	}
+	if o.codec == nil {
+		return nil, fmt.Errorf("%w: codec must not be nil", ErrInvalidOption)
+	}
+	if o.encryptionKey != nil {
+		if _, err := aes.NewCipher(o.encryptionKey); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidOption, err)
+		}
+	}
+	if o.snapshotPath != "" && o.snapshotInterval <= 0 {
+		return nil, fmt.Errorf("%w: snapshot interval must be greater than zero", ErrInvalidOption)
+	}
+	if o.noLocking && o.snapshotPath != "" {
+		return nil, fmt.Errorf("%w: auto snapshot requires locking", ErrInvalidOption)
+	}
+	if o.noLocking && o.logPath != "" {
+		return nil, fmt.Errorf("%w: append log requires locking", ErrInvalidOption)
+	}
+	c := &lruCache[Key, Val]{
+		capacity:     o.capacity,
+		store:        make(map[Key]*cacheEntry[Key, Val]),
+		order:        dll.New(),
+		weigher:      o.weigher,
+		maxWeight:    o.maxWeight,
+		sizer:        o.sizer,
+		clock:        o.clock,
+		logger:       o.logger,
+		codec:        o.codec,
+		loader:       o.loader,
+		refreshAfter: o.refreshAfter,
+		warmWorkers:  o.warmWorkers,
+		equal:        o.equal,
+		onEvict:      o.onEvict,
+		spill:        o.spill,
+		packer:       o.pack,
+		unpacker:     o.unpack,
+		stats:        newStatsCounter(o.histograms),
+		mu:           newLocker(o.noLocking),
+	}
+	if o.doorkeeper > 0 {
+		c.doorkeeper = newBloomFilter[Key](o.doorkeeper, 0.01)
+	}
+	if o.negativeTTL < 0 {
+		return nil, ErrInvalidTTL
+	}
+	if o.negativeTTL > 0 {
+		neg, err := newNegativeCache[Key](o.negativeTTL, o.clock)
+		if err != nil {
+			return nil, err
+		}
+		c.negative = neg
+	}
+	if o.prefixIndex {
+		idx, err := newPrefixIndex[Key]()
+		if err != nil {
+			return nil, err
+		}
+		c.prefixes = idx
+	}
+	if o.eventBuf > 0 {
+		c.events = make(chan Event[Key, Val], o.eventBuf)
+	}
+	if o.snapshotPath != "" {
+		entries, err := readSnapshotFile[Key, Val](o.snapshotPath, o.codec)
+		if err != nil {
+			return nil, err
+		}
+		c.restore(entries)
 	}
-	return &lruCache[Key, Val]{
-		capacity: cap,
-		store:    make(map[Key]Val),
-		order:    dll.New(),
-	}, nil
+	if o.logPath != "" {
+		if err := c.openLog(o.logPath); err != nil {
+			return nil, err
+		}
+	}
+	if o.snapshotPath != "" {
+		var ctx context.Context
+		ctx, c.stopSnapshot = context.WithCancel(context.Background())
+		c.snapshotPath = o.snapshotPath
+		c.snapshotDone = make(chan struct{})
+		go autoSnapshot(ctx, o.snapshotPath, o.snapshotInterval, c, c.clock, c.logger, c.snapshotDone)
+	}
+	if o.accessBuf > 0 {
+		c.accesses = make(chan Key, o.accessBuf)
+		c.stop = make(chan struct{})
+		c.drained = make(chan struct{})
+		go c.drain(o.accessBuf)
+	}
+	return c, nil
 }
 
 func (c *lruCache[Key, Val]) Get(k Key) (Val, bool) {
+	v, ok := c.lookup(k)
+	if !ok && c.spill != nil {
+		v, ok = c.promote(k)
+	}
+	c.stats.record(ok)
+	if ok || c.loader == nil {
+		return v, ok
+	}
+	v, err := c.load(context.Background(), k)
+	return v, err == nil
+}
+
+func (c *lruCache[Key, Val]) GetContext(ctx context.Context, k Key) (Val, error) {
+	v, ok := c.lookup(k)
+	if !ok && c.spill != nil {
+		v, ok = c.promote(k)
+	}
+	c.stats.record(ok)
+	if ok {
+		return v, nil
+	}
+	if c.loader == nil {
+		var z Val
+		return z, ErrNotFound
+	}
+	return c.load(ctx, k)
+}
+
+func (c *lruCache[Key, Val]) lookup(k Key) (Val, bool) {
+	if c.accesses != nil {
+		c.mu.RLock()
+		e, ok := c.store[k]
+		c.mu.RUnlock()
+
+		if ok && !c.stale(e) {
+			now := c.clock.Now()
+			e.access(now)
+			if refreshDue(e, c.refreshAfter, now) {
+				c.refresh(e)
+			}
+			select {
+			case c.accesses <- k:
+			default:
+			}
+			return c.checked(e)
+		}
+		var z Val
+		return z, false
+	}
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	if v, ok := c.store[k]; ok {
+	if e, ok := c.live(k); ok {
+		v, err := c.unpacked(e)
+		if err != nil {
+			c.corrupt(e, err)
+			return v, false
+		}
+		now := c.clock.Now()
+		e.access(now)
 		c.recentify(k)
+		if refreshDue(e, c.refreshAfter, now) {
+			c.refresh(e)
+		}
 		return v, true
 	}
 	var z Val
@@ -38,27 +265,445 @@ func (c *lruCache[Key, Val]) Get(k Key) (Val, bool) {
 
 func (c *lruCache[Key, Val]) Put(k Key, v Val) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
+	c.put(k, v)
+}
 
-	if _, ok := c.store[k]; ok {
-		c.store[k] = v
-		c.recentify(k)
+func (c *lruCache[Key, Val]) Add(k Key, v Val) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.live(k); ok {
+		return false
+	}
+	return c.insert(k, v)
+}
+
+func (c *lruCache[Key, Val]) GetOrCompute(k Key, fn func() Val) Val {
+	if v, ok := c.lookup(k); ok {
+		return v
+	}
+	v, _ := c.flights.do(context.Background(), k, func(context.Context) (Val, error) {
+		return c.settle(k, fn()), nil
+	})
+	return v
+}
+
+func (c *lruCache[Key, Val]) Delete(k Key) bool {
+	c.negative.forget(k)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if _, ok := c.live(k); ok {
+		c.drop(k)
+		return true
+	}
+	return c.spill != nil && c.spill.Delete(k)
+}
+
+func (c *lruCache[Key, Val]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.store) - c.staleCount
+}
+
+func (c *lruCache[Key, Val]) Stats() Stats {
+	return c.stats.snapshot(c.Len())
+}
+
+func (c *lruCache[Key, Val]) Clear() {
+	c.negative.clear()
+
+	c.mu.Lock()
+	defer c.unlock()
+	c.clear()
+}
+
+func (c *lruCache[Key, Val]) clear() {
+	c.store = make(map[Key]*cacheEntry[Key, Val])
+	c.order.Clear()
+	c.weight = 0
+	c.staleCount = 0
+	c.tags.clear()
+	c.prefixes.clear()
+	c.priorities.clear()
+	var (
+		k Key
+		v Val
+	)
+	c.emit(EventClear, k, v)
+	c.aof.append(logClear, nil)
+	if c.spill != nil {
+		c.spill.Clear()
+	}
+}
+
+func (c *lruCache[Key, Val]) GetMany(keys []Key) map[Key]Val {
+	c.mu.Lock()
+	defer c.unlock()
+
+	found := make(map[Key]Val, len(keys))
+	for _, k := range keys {
+		e, ok := c.live(k)
+		var (
+			v   Val
+			err error
+		)
+		if ok {
+			if v, err = c.unpacked(e); err != nil {
+				c.corrupt(e, err)
+				ok = false
+			}
+		}
+		c.stats.record(ok)
+		if ok {
+			e.access(c.clock.Now())
+			c.recentify(k)
+			found[k] = v
+		}
+	}
+	return found
+}
+
+func (c *lruCache[Key, Val]) PutMany(entries map[Key]Val) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for k, v := range entries {
+		c.put(k, v)
+	}
+}
+
+func (c *lruCache[Key, Val]) DeleteMany(keys []Key) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	n := 0
+	for _, k := range keys {
+		if _, ok := c.live(k); ok {
+			c.drop(k)
+			n++
+		} else if c.spill != nil && c.spill.Delete(k) {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *lruCache[Key, Val]) Pop(k Key) (Val, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.live(k); ok {
+		c.drop(k)
+		v, err := c.unpacked(e)
+		return v, err == nil
+	}
+	var z Val
+	return z, false
+}
+
+func (c *lruCache[Key, Val]) CompareAndSwap(k Key, old, new Val) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.live(k); ok && c.equal(c.value(e), old) {
+		c.replace(e, new)
+		return true
+	}
+	return false
+}
+
+func (c *lruCache[Key, Val]) CompareAndDelete(k Key, old Val) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.live(k); ok && c.equal(c.value(e), old) {
+		c.drop(k)
+		return true
+	}
+	return false
+}
+
+func (c *lruCache[Key, Val]) Update(k Key, fn func(old Val, exists bool) (Val, bool)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var old Val
+	e, exists := c.live(k)
+	if exists {
+		old = c.value(e)
+	}
+	v, keep := fn(old, exists)
+	switch {
+	case keep && exists:
+		c.replace(e, v)
+	case keep:
+		c.insert(k, v)
+	case exists:
+		c.drop(k)
+	}
+}
+
+func (c *lruCache[Key, Val]) load(ctx context.Context, k Key) (Val, error) {
+	if err, ok := c.negative.lookup(k); ok {
+		var z Val
+		return z, err
+	}
+	return c.flights.do(ctx, k, func(ctx context.Context) (Val, error) {
+		start := c.clock.Now()
+		v, err := c.loader(ctx, k)
+		c.stats.loaded(c.clock.Now().Sub(start), err)
+		if err != nil {
+			c.logger.Debug("cache load failed", "key", k, "err", err)
+			c.negative.record(k, err)
+			var z Val
+			return z, err
+		}
+		return c.settle(k, v), nil
+	})
+}
+
+func (c *lruCache[Key, Val]) settle(k Key, v Val) Val {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.live(k); ok {
+		cur, err := c.unpacked(e)
+		if err == nil {
+			return cur
+		}
+		c.corrupt(e, err)
+	}
+	c.insert(k, v)
+	return v
+}
+
+func (c *lruCache[Key, Val]) put(k Key, v Val) {
+	if e, ok := c.live(k); ok {
+		c.replace(e, v)
 	} else {
-		if len(c.store) == c.capacity {
-			c.evict()
+		c.insert(k, v)
+	}
+}
+
+func (c *lruCache[Key, Val]) insert(k Key, v Val) bool {
+	return c.admit(k) && c.link(newCacheEntry(k, c.pack(v), 0, c.clock.Now()))
+}
+
+func (c *lruCache[Key, Val]) admit(k Key) bool {
+	return c.doorkeeper == nil || c.doorkeeper.admit(k)
+}
+
+func (c *lruCache[Key, Val]) link(e *cacheEntry[Key, Val]) bool {
+	w := c.weigh(e.key, e.value())
+	if c.maxWeight > 0 && w > c.maxWeight {
+		return false
+	}
+	e.weight = w
+	e.gen = c.generation.Load()
+	c.store[e.key] = e
+	c.order.Add(e.key)
+	c.prefixes.add(e.key)
+	c.priorities.add(e.priority)
+	c.weight += w
+	c.emit(EventPut, e.key, c.value(e))
+	c.aof.append(logPut, e)
+	c.shrink()
+	return c.store[e.key] == e
+}
+
+func (c *lruCache[Key, Val]) replace(e *cacheEntry[Key, Val], v Val) {
+	stored := c.pack(v)
+	w := c.weigh(e.key, stored)
+	if c.maxWeight > 0 && w > c.maxWeight {
+		c.notify(e, EvictionCapacity)
+		c.remove(e.key)
+		return
+	}
+	c.weight += w - e.weight
+	e.weight = w
+	e.setValue(stored)
+	e.written.Store(c.clock.Now().UnixNano())
+	c.recentify(e.key)
+	c.emit(EventPut, e.key, v)
+	c.aof.append(logPut, e)
+	c.shrink()
+}
+
+func (c *lruCache[Key, Val]) weigh(k Key, v Val) int64 {
+	if c.weigher == nil {
+		return 1
+	}
+	return c.weigher(k, v)
+}
+
+func (c *lruCache[Key, Val]) shrink() {
+	n := 0
+	for len(c.store) > c.capacity || (c.maxWeight > 0 && c.weight > c.maxWeight) {
+		if !c.evict() {
+			break
 		}
-		c.store[k] = v
-		c.order.Add(k)
+		n++
 	}
+	logEvictions(c.logger, n)
 }
 
-func (c *lruCache[Key, Val]) evict() {
-	t, _ := c.order.Get(0)
-	c.order.Remove(0)
-	delete(c.store, t.(Key))
+func (c *lruCache[Key, Val]) evict() bool {
+	lowest := c.priorities.lowest()
+	var victim *cacheEntry[Key, Val]
+	it := c.order.Iterator()
+	for it.Next() {
+		e := c.store[it.Value().(Key)]
+		if e.pinned.Load() && !c.stale(e) {
+			continue
+		}
+		if victim == nil || e.priority < victim.priority {
+			victim = e
+		}
+		if victim.priority <= lowest {
+			break
+		}
+	}
+	if victim == nil {
+		return false
+	}
+	c.notify(victim, EvictionCapacity)
+	c.remove(victim.key)
+	return true
+}
+
+func (c *lruCache[Key, Val]) notify(e *cacheEntry[Key, Val], reason EvictionReason) {
+	c.stats.evicted(reason, e.created, c.clock)
+	c.emit(evictionEvent(reason), e.key, c.value(e))
+	if c.onEvict != nil || c.spill != nil {
+		c.evicted = append(c.evicted, evictedEntry[Key, Val]{e.key, c.value(e), reason, e.expiresAt()})
+	}
+}
+
+func (c *lruCache[Key, Val]) Events() <-chan Event[Key, Val] {
+	return c.events
+}
+
+func (c *lruCache[Key, Val]) DroppedEvents() uint64 {
+	return c.stats.droppedEvents.Load()
+}
+
+func (c *lruCache[Key, Val]) emit(t EventType, k Key, v Val) {
+	if c.events == nil {
+		return
+	}
+	select {
+	case c.events <- Event[Key, Val]{t, k, v}:
+	default:
+		c.stats.droppedEvents.Add(1)
+	}
+}
+
+func (c *lruCache[Key, Val]) unlock() {
+	evicted := c.evicted
+	c.evicted = nil
+	if c.spill != nil {
+		for _, e := range evicted {
+			if e.reason == EvictionCapacity {
+				c.spill.Spill(e.key, e.val, e.expiresAt)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	if c.onEvict != nil {
+		for _, e := range evicted {
+			c.onEvict(e.key, e.val, e.reason)
+		}
+	}
+}
+
+func (c *lruCache[Key, Val]) drop(k Key) {
+	e := c.store[k]
+	if c.spill != nil {
+		c.spill.Delete(k)
+	}
+	c.emit(EventDelete, k, c.value(e))
+	c.aof.append(logDelete, e)
+	c.remove(k)
+}
+
+func (c *lruCache[Key, Val]) remove(k Key) {
+	c.order.Remove(c.order.IndexOf(k))
+	e := c.store[k]
+	c.weight -= e.weight
+	if c.stale(e) {
+		c.staleCount--
+	}
+	delete(c.store, k)
+	c.tags.remove(k)
+	c.prefixes.remove(k)
+	c.priorities.remove(e.priority)
 }
 
 func (c *lruCache[Key, Val]) recentify(k Key) {
 	c.order.Remove(c.order.IndexOf(k))
 	c.order.Add(k)
 }
+
+func (c *lruCache[Key, Val]) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	if c.stop != nil {
+		close(c.stop)
+		<-c.drained
+	}
+	var err error
+	if c.stopSnapshot != nil {
+		c.stopSnapshot()
+		<-c.snapshotDone
+		err = saveSnapshotFile(c.snapshotPath, c, c.logger)
+	}
+	if c.stopLog != nil {
+		c.stopLog()
+		<-c.logDone
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+	if c.aof != nil {
+		err = errors.Join(err, c.aof.close())
+		c.aof = nil
+	}
+	return err
+}
+
+func (c *lruCache[Key, Val]) drain(batchSize int) {
+	defer close(c.drained)
+
+	batch := make([]Key, 0, batchSize)
+	for {
+		select {
+		case k := <-c.accesses:
+			batch = append(batch, k)
+		case <-c.stop:
+			return
+		}
+	collect:
+		for len(batch) < batchSize {
+			select {
+			case k := <-c.accesses:
+				batch = append(batch, k)
+			default:
+				break collect
+			}
+		}
+
+		c.mu.Lock()
+		for _, k := range batch {
+			if _, ok := c.store[k]; ok {
+				c.recentify(k)
+			}
+		}
+		c.unlock()
+		batch = batch[:0]
+	}
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUGetWithLoader(t *testing.T) {
+	c, err := NewLRU(10, WithLoader(func(ctx context.Context, k string) (int, error) {
+		return len(k), nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if v, ok := c.Get("abc"); !ok || v != 3 {
+			t.Errorf("Get = %v, %v; want 3, true", v, ok)
+		}
+		if v, ok := c.Get("abc"); !ok || v != 3 {
+			t.Errorf("cached Get = %v, %v; want 3, true", v, ok)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Get with a loader did not return")
+	}
+}
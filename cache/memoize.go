@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const memoizeCapacity = 1 << 10
+
+type MemoizeOption[Key comparable, Val any] func(*memoizeOptions[Key, Val])
+
+type memoizeOptions[Key comparable, Val any] struct {
+	cache    Cache[Key, Val]
+	ttl      time.Duration
+	errTTL   time.Duration
+	errMatch func(error) bool
+}
+
+func WithMemoizeCache[Key comparable, Val any](c Cache[Key, Val]) MemoizeOption[Key, Val] {
+	return func(o *memoizeOptions[Key, Val]) {
+		o.cache = c
+	}
+}
+
+func WithMemoizeTTL[Key comparable, Val any](d time.Duration) MemoizeOption[Key, Val] {
+	return func(o *memoizeOptions[Key, Val]) {
+		o.ttl = d
+	}
+}
+
+func WithMemoizeErrors[Key comparable, Val any](ttl time.Duration, match func(error) bool) MemoizeOption[Key, Val] {
+	return func(o *memoizeOptions[Key, Val]) {
+		o.errTTL = ttl
+		o.errMatch = match
+	}
+}
+
+type memoizer[Key comparable, Val any] struct {
+	fn       func(context.Context, Key) (Val, error)
+	cache    Cache[Key, Val]
+	ttl      time.Duration
+	errs     *ttlCache[Key, error]
+	errMatch func(error) bool
+	flights  flightGroup[Key, Val]
+}
+
+func Memoize[Key comparable, Val any](fn func(context.Context, Key) (Val, error), opts ...MemoizeOption[Key, Val]) func(context.Context, Key) (Val, error) {
+	m, err := newMemoizer(fn, opts)
+	if err != nil {
+		return func(context.Context, Key) (Val, error) {
+			var z Val
+			return z, err
+		}
+	}
+	return m.call
+}
+
+func newMemoizer[Key comparable, Val any](fn func(context.Context, Key) (Val, error), opts []MemoizeOption[Key, Val]) (*memoizer[Key, Val], error) {
+	var o memoizeOptions[Key, Val]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("%w: function must not be nil", ErrInvalidOption)
+	}
+	if o.ttl < 0 || o.errTTL < 0 {
+		return nil, ErrInvalidTTL
+	}
+	m := &memoizer[Key, Val]{fn: fn, cache: o.cache, errMatch: o.errMatch}
+	switch {
+	case m.cache == nil && o.ttl > 0:
+		c, err := NewTTL[Key, Val](o.ttl, false, WithCapacity[Key, Val](memoizeCapacity))
+		if err != nil {
+			return nil, err
+		}
+		m.cache = c
+	case m.cache == nil:
+		c, err := NewLRU[Key, Val](memoizeCapacity)
+		if err != nil {
+			return nil, err
+		}
+		m.cache = c
+	case o.ttl > 0:
+		if _, ok := m.cache.(ttlPutter[Key, Val]); !ok {
+			return nil, fmt.Errorf("%w: cache does not support per-entry ttl", ErrInvalidOption)
+		}
+		m.ttl = o.ttl
+	}
+	if o.errTTL > 0 {
+		errs, err := NewTTL[Key, error](o.errTTL, false, WithCapacity[Key, error](negativeCapacity))
+		if err != nil {
+			return nil, err
+		}
+		m.errs = errs
+		if m.errMatch == nil {
+			m.errMatch = func(error) bool { return true }
+		}
+	}
+	return m, nil
+}
+
+func (m *memoizer[Key, Val]) call(ctx context.Context, k Key) (Val, error) {
+	if v, ok := m.cache.Get(k); ok {
+		return v, nil
+	}
+	if m.errs != nil {
+		if err, ok := m.errs.Get(k); ok {
+			var z Val
+			return z, err
+		}
+	}
+	return m.flights.do(ctx, k, func(ctx context.Context) (Val, error) {
+		v, err := m.fn(ctx, k)
+		if err != nil {
+			if m.errs != nil && m.errMatch(err) {
+				m.errs.Put(k, err)
+			}
+			return v, err
+		}
+		if m.ttl > 0 {
+			m.cache.(ttlPutter[Key, Val]).PutWithTTL(k, v, m.ttl)
+		} else {
+			m.cache.Put(k, v)
+		}
+		return v, nil
+	})
+}
@@ -0,0 +1,41 @@
+package cache
+
+import "unsafe"
+
+const wordSize = int64(unsafe.Sizeof(uintptr(0)))
+
+func entryBytes[Key comparable, Val any]() int64 {
+	var k Key
+	return int64(unsafe.Sizeof(cacheEntry[Key, Val]{})) + int64(unsafe.Sizeof(k)) + wordSize
+}
+
+func (c *lruCache[Key, Val]) MemoryBytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var k Key
+	perEntry := entryBytes[Key, Val]() + int64(unsafe.Sizeof(k)) + 4*wordSize
+	total := int64(len(c.store)) * perEntry
+	if c.sizer != nil {
+		for k, e := range c.store {
+			total += c.sizer(k, e.value())
+		}
+	}
+	return total
+}
+
+func (c *ttlCache[Key, Val]) MemoryBytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	perEntry := entryBytes[Key, Val]() + 6*wordSize
+	total := int64(c.size) * perEntry
+	if c.sizer != nil {
+		c.store.Range(func(_, v any) bool {
+			e := v.(*cacheEntry[Key, Val])
+			total += c.sizer(e.key, e.value())
+			return true
+		})
+	}
+	return total
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type negativeCache[Key comparable] struct {
+	errs *ttlCache[Key, error]
+}
+
+func newNegativeCache[Key comparable](ttl time.Duration, clock Clock) (*negativeCache[Key], error) {
+	errs, err := NewTTL(ttl, false, WithCapacity[Key, error](negativeCapacity), WithClock[Key, error](clock))
+	if err != nil {
+		return nil, err
+	}
+	return &negativeCache[Key]{errs}, nil
+}
+
+func (n *negativeCache[Key]) lookup(k Key) (error, bool) {
+	if n == nil {
+		return nil, false
+	}
+	return n.errs.Get(k)
+}
+
+func (n *negativeCache[Key]) record(k Key, err error) {
+	if n == nil {
+		return
+	}
+	switch {
+	case errors.Is(err, ErrCircuitOpen), errors.Is(err, ErrRateLimited):
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+	default:
+		n.errs.Put(k, err)
+	}
+}
+
+func (n *negativeCache[Key]) forget(k Key) {
+	if n != nil {
+		n.errs.Delete(k)
+	}
+}
+
+func (n *negativeCache[Key]) clear() {
+	if n != nil {
+		n.errs.Clear()
+	}
+}
@@ -0,0 +1,22 @@
+package cache
+
+type nopCache[Key comparable, Val any] struct{}
+
+func NewNop[Key comparable, Val any]() *nopCache[Key, Val] {
+	return &nopCache[Key, Val]{}
+}
+
+func (nopCache[Key, Val]) Get(k Key) (Val, bool) {
+	var z Val
+	return z, false
+}
+
+func (nopCache[Key, Val]) Put(k Key, v Val) {}
+
+func (nopCache[Key, Val]) Delete(k Key) bool { return false }
+
+func (nopCache[Key, Val]) Len() int { return 0 }
+
+func (nopCache[Key, Val]) Clear() {}
+
+func (nopCache[Key, Val]) Stats() Stats { return Stats{} }
@@ -0,0 +1,361 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+type LoaderFunc[Key comparable, Val any] func(ctx context.Context, k Key) (Val, error)
+
+type ExpirationBasis int
+
+const (
+	ExpireAfterWrite ExpirationBasis = iota + 1
+	ExpireAfterAccess
+	ExpireAfterCreate
+)
+
+type Option[Key comparable, Val any] func(*options[Key, Val])
+
+type options[Key comparable, Val any] struct {
+	loader           LoaderFunc[Key, Val]
+	equal            func(a, b Val) bool
+	ttl              time.Duration
+	capacity         int
+	resetOnRead      bool
+	onEvict          func(k Key, v Val, reason EvictionReason)
+	expiration       ExpirationBasis
+	wheelTick        time.Duration
+	wheelSizes       []int
+	jitter           float64
+	batchSize        int
+	janitor          time.Duration
+	accessBuf        int
+	weigher          func(k Key, v Val) int64
+	maxWeight        int64
+	sizer            func(k Key, v Val) int64
+	doorkeeper       int
+	clock            Clock
+	noLocking        bool
+	logger           *slog.Logger
+	eventBuf         int
+	histograms       bool
+	codec            Codec
+	snapshotPath     string
+	snapshotInterval time.Duration
+	logPath          string
+	spill            SpillStore[Key, Val]
+	pack             func(v Val) Val
+	unpack           func(v Val) (Val, error)
+	encryptionKey    []byte
+	warmWorkers      int
+	prefixIndex      bool
+	maxStale         time.Duration
+	xfetchBeta       float64
+	refreshAfter     time.Duration
+	softTTL          time.Duration
+	negativeTTL      time.Duration
+	breakerThreshold int
+	breakerOpenFor   time.Duration
+	breakerProbes    int
+	loaderRate       float64
+	loaderBurst      int
+	batchLoader      BatchLoaderFunc[Key, Val]
+	batchWindow      time.Duration
+	batchMax         int
+	maxLifetime      time.Duration
+	maxIdle          time.Duration
+}
+
+func WithLoader[Key comparable, Val any](fn LoaderFunc[Key, Val]) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.loader = fn
+	}
+}
+
+func WithEqual[Key comparable, Val any](fn func(a, b Val) bool) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.equal = fn
+	}
+}
+
+func WithExpirationBasis[Key comparable, Val any](b ExpirationBasis) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.expiration = b
+	}
+}
+
+func WithTTL[Key comparable, Val any](d time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.ttl = d
+	}
+}
+
+func WithCapacity[Key comparable, Val any](n int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.capacity = n
+	}
+}
+
+func WithMaxEntries[Key comparable, Val any](n int) Option[Key, Val] {
+	return WithCapacity[Key, Val](n)
+}
+
+func WithMaxIdle[Key comparable, Val any](d time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.maxIdle = d
+	}
+}
+
+func WithMaxLifetime[Key comparable, Val any](d time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.maxLifetime = d
+	}
+}
+
+func WithResetOnRead[Key comparable, Val any]() Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.resetOnRead = true
+	}
+}
+
+func WithEvictionCallback[Key comparable, Val any](fn func(k Key, v Val, reason EvictionReason)) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.onEvict = fn
+	}
+}
+
+func WithTimingWheel[Key comparable, Val any](tick time.Duration, sizes ...int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.wheelTick = tick
+		o.wheelSizes = sizes
+	}
+}
+
+func WithJitter[Key comparable, Val any](fraction float64) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.jitter = fraction
+	}
+}
+
+func WithCleanupBatch[Key comparable, Val any](n int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.batchSize = n
+	}
+}
+
+func WithJanitor[Key comparable, Val any](interval time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.janitor = interval
+	}
+}
+
+func WithAccessBuffer[Key comparable, Val any](size int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.accessBuf = size
+	}
+}
+
+func WithWeigher[Key comparable, Val any](fn func(k Key, v Val) int64) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.weigher = fn
+	}
+}
+
+func WithMaxWeight[Key comparable, Val any](w int64) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.maxWeight = w
+	}
+}
+
+func WithSizer[Key comparable, Val any](fn func(k Key, v Val) int64) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.sizer = fn
+	}
+}
+
+func WithDoorkeeper[Key comparable, Val any](expected int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.doorkeeper = expected
+	}
+}
+
+func WithClock[Key comparable, Val any](clock Clock) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.clock = clock
+	}
+}
+
+func WithNoLocking[Key comparable, Val any]() Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.noLocking = true
+	}
+}
+
+func WithLogger[Key comparable, Val any](logger *slog.Logger) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.logger = logger
+	}
+}
+
+func WithEvents[Key comparable, Val any](buffer int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.eventBuf = buffer
+	}
+}
+
+func WithHistograms[Key comparable, Val any]() Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.histograms = true
+	}
+}
+
+func WithCodec[Key comparable, Val any](codec Codec) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.codec = codec
+	}
+}
+
+func WithAutoSnapshot[Key comparable, Val any](path string, interval time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.snapshotPath = path
+		o.snapshotInterval = interval
+	}
+}
+
+func WithAppendLog[Key comparable, Val any](path string) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.logPath = path
+	}
+}
+
+func WithSpillover[Key comparable, Val any](store SpillStore[Key, Val]) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.spill = store
+	}
+}
+
+func WithWarmConcurrency[Key comparable, Val any](n int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.warmWorkers = n
+	}
+}
+
+func WithPrefixIndex[Key comparable, Val any]() Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.prefixIndex = true
+	}
+}
+
+func WithStaleWhileRevalidate[Key comparable, Val any](maxStale time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.maxStale = maxStale
+	}
+}
+
+func WithXFetch[Key comparable, Val any](beta float64) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.xfetchBeta = beta
+	}
+}
+
+func WithRefreshAfter[Key comparable, Val any](d time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.refreshAfter = d
+	}
+}
+
+func WithSoftTTL[Key comparable, Val any](d time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.softTTL = d
+	}
+}
+
+func WithNegativeCaching[Key comparable, Val any](ttl time.Duration) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.negativeTTL = ttl
+	}
+}
+
+func WithCircuitBreaker[Key comparable, Val any](threshold int, openFor time.Duration, probes int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.breakerThreshold = threshold
+		o.breakerOpenFor = openFor
+		o.breakerProbes = probes
+	}
+}
+
+func WithLoaderRateLimit[Key comparable, Val any](rate float64, burst int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.loaderRate = rate
+		o.loaderBurst = burst
+	}
+}
+
+func WithBatchLoader[Key comparable, Val any](fn BatchLoaderFunc[Key, Val], window time.Duration, maxBatch int) Option[Key, Val] {
+	return func(o *options[Key, Val]) {
+		o.batchLoader = fn
+		o.batchWindow = window
+		o.batchMax = maxBatch
+	}
+}
+
+func (o *options[Key, Val]) wrapLoader() error {
+	if o.batchLoader != nil {
+		if o.loader != nil {
+			return fmt.Errorf("%w: batch loader cannot be combined with a loader", ErrInvalidOption)
+		}
+		b, err := newBatcher(o.batchLoader, o.batchWindow, o.batchMax)
+		if err != nil {
+			return err
+		}
+		o.loader = b.load
+	}
+	if o.breakerThreshold != 0 || o.breakerOpenFor != 0 {
+		if o.loader == nil {
+			return fmt.Errorf("%w: circuit breaker requires a loader", ErrInvalidOption)
+		}
+		b, err := newCircuitBreaker(o.breakerThreshold, o.breakerOpenFor, o.breakerProbes, o.clock)
+		if err != nil {
+			return err
+		}
+		o.loader = breakerLoader(b, o.loader)
+	}
+	if o.loaderRate != 0 || o.loaderBurst != 0 {
+		if o.loader == nil {
+			return fmt.Errorf("%w: loader rate limit requires a loader", ErrInvalidOption)
+		}
+		b, err := newTokenBucket(o.loaderRate, o.loaderBurst, o.clock)
+		if err != nil {
+			return err
+		}
+		o.loader = rateLimitedLoader(b, o.loader)
+	}
+	return nil
+}
+
+func defaultEqual[Val any](a, b Val) bool {
+	if reflect.ValueOf(&a).Elem().Comparable() {
+		return any(a) == any(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func applyOptions[Key comparable, Val any](opts []Option[Key, Val]) options[Key, Val] {
+	o := options[Key, Val]{
+		equal:  defaultEqual[Val],
+		clock:  systemClock{},
+		logger: slog.New(slog.DiscardHandler),
+		codec:  GobCodec,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.encryptionKey != nil && o.codec != nil {
+		o.codec = encryptedCodec{o.codec, o.encryptionKey}
+	}
+	return o
+}
@@ -0,0 +1,49 @@
+package cache
+
+func (c *lruCache[Key, Val]) Pin(k Key) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.live(k)
+	if ok {
+		e.pinned.Store(true)
+	}
+	return ok
+}
+
+func (c *lruCache[Key, Val]) Unpin(k Key) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.live(k)
+	if !ok || !e.pinned.Swap(false) {
+		return false
+	}
+	c.shrink()
+	return true
+}
+
+func (c *ttlCache[Key, Val]) Pin(k Key) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.entry(k)
+	if !ok || c.expired(e) {
+		return false
+	}
+	e.pinned.Store(true)
+	c.expiries.remove(e)
+	return true
+}
+
+func (c *ttlCache[Key, Val]) Unpin(k Key) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.entry(k)
+	if !ok || !e.pinned.Swap(false) {
+		return false
+	}
+	c.expiries.schedule(e)
+	return true
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emirpasic/gods/trees/redblacktree"
+)
+
+type prefixIndex struct {
+	tree *redblacktree.Tree
+}
+
+func newPrefixIndex[Key comparable]() (*prefixIndex, error) {
+	var k Key
+	if _, ok := any(k).(string); !ok {
+		return nil, fmt.Errorf("%w: prefix index requires string keys", ErrInvalidOption)
+	}
+	return &prefixIndex{redblacktree.NewWithStringComparator()}, nil
+}
+
+func (p *prefixIndex) add(k any) {
+	if p != nil {
+		p.tree.Put(k, nil)
+	}
+}
+
+func (p *prefixIndex) remove(k any) {
+	if p != nil {
+		p.tree.Remove(k)
+	}
+}
+
+func (p *prefixIndex) clear() {
+	if p != nil {
+		p.tree.Clear()
+	}
+}
+
+func (p *prefixIndex) match(prefix string) []string {
+	node, ok := p.tree.Ceiling(prefix)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for it := p.tree.IteratorAt(node); ; {
+		k := it.Key().(string)
+		if !strings.HasPrefix(k, prefix) {
+			break
+		}
+		keys = append(keys, k)
+		if !it.Next() {
+			break
+		}
+	}
+	return keys
+}
+
+func hasPrefix[Key comparable](k Key, prefix string) bool {
+	s, ok := any(k).(string)
+	return ok && strings.HasPrefix(s, prefix)
+}
+
+func (c *lruCache[Key, Val]) DeletePrefix(prefix string) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var keys []Key
+	if c.prefixes != nil {
+		for _, s := range c.prefixes.match(prefix) {
+			keys = append(keys, any(s).(Key))
+		}
+	} else {
+		for k := range c.store {
+			if hasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+	}
+	for _, k := range keys {
+		c.drop(k)
+	}
+	return len(keys)
+}
+
+func (c *ttlCache[Key, Val]) DeletePrefix(prefix string) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var entries []*cacheEntry[Key, Val]
+	if c.prefixes != nil {
+		for _, s := range c.prefixes.match(prefix) {
+			if e, ok := c.entry(any(s).(Key)); ok {
+				entries = append(entries, e)
+			}
+		}
+	} else {
+		c.store.Range(func(_, v any) bool {
+			if e := v.(*cacheEntry[Key, Val]); hasPrefix(e.key, prefix) {
+				entries = append(entries, e)
+			}
+			return true
+		})
+	}
+	n := 0
+	for _, e := range entries {
+		if c.discard(e) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,116 @@
+package cache
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota - 1
+	PriorityNormal
+	PriorityHigh
+)
+
+type priorityLevels struct {
+	counts map[Priority]int
+}
+
+func (l *priorityLevels) add(p Priority) {
+	if l.counts == nil {
+		l.counts = make(map[Priority]int)
+	}
+	l.counts[p]++
+}
+
+func (l *priorityLevels) remove(p Priority) {
+	if l.counts[p]--; l.counts[p] <= 0 {
+		delete(l.counts, p)
+	}
+}
+
+func (l *priorityLevels) lowest() Priority {
+	lowest, first := PriorityNormal, true
+	for p := range l.counts {
+		if first || p < lowest {
+			lowest, first = p, false
+		}
+	}
+	return lowest
+}
+
+func (l *priorityLevels) mixed() bool {
+	return len(l.counts) > 1
+}
+
+func (l *priorityLevels) clear() {
+	l.counts = nil
+}
+
+func (c *lruCache[Key, Val]) PutWithPriority(k Key, v Val, p Priority) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.live(k); ok {
+		c.prioritize(e, p)
+		c.replace(e, v)
+		return
+	}
+	if c.admit(k) {
+		e := newCacheEntry(k, c.pack(v), 0, c.clock.Now())
+		e.priority = p
+		c.link(e)
+	}
+}
+
+func (c *lruCache[Key, Val]) SetPriority(k Key, p Priority) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.live(k)
+	if ok {
+		c.prioritize(e, p)
+	}
+	return ok
+}
+
+func (c *lruCache[Key, Val]) prioritize(e *cacheEntry[Key, Val], p Priority) {
+	c.priorities.remove(e.priority)
+	e.priority = p
+	c.priorities.add(p)
+}
+
+func (c *ttlCache[Key, Val]) PutWithPriority(k Key, v Val, p Priority) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && !c.expired(e) {
+		c.prioritize(e, p)
+		c.put(k, v, c.timeToLive)
+		return
+	}
+	if c.admit(k) {
+		e := newCacheEntry(k, c.pack(v), c.lifetime(c.timeToLive), c.clock.Now())
+		e.priority = p
+		c.link(e)
+	}
+}
+
+func (c *ttlCache[Key, Val]) SetPriority(k Key, p Priority) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.entry(k)
+	if !ok || c.expired(e) {
+		return false
+	}
+	c.prioritize(e, p)
+	return true
+}
+
+func (c *ttlCache[Key, Val]) prioritize(e *cacheEntry[Key, Val], p Priority) {
+	c.priorities.remove(e.priority)
+	e.priority = p
+	c.priorities.add(p)
+}
+
+func expiresBefore[Key comparable, Val any](a, b *cacheEntry[Key, Val]) bool {
+	ea, eb := a.expiresAt(), b.expiresAt()
+	return !ea.IsZero() && (eb.IsZero() || ea.Before(eb))
+}
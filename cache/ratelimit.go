@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	clock  Clock
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, clock Clock) (*tokenBucket, error) {
+	if rate <= 0 {
+		return nil, fmt.Errorf("%w: loader rate must be greater than zero", ErrInvalidOption)
+	}
+	if burst <= 0 {
+		return nil, fmt.Errorf("%w: loader burst must be greater than zero", ErrInvalidOption)
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), clock: clock, tokens: float64(burst), last: clock.Now()}, nil
+}
+
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func rateLimitedLoader[Key comparable, Val any](b *tokenBucket, fn LoaderFunc[Key, Val]) LoaderFunc[Key, Val] {
+	return func(ctx context.Context, k Key) (Val, error) {
+		if !b.allow() {
+			var z Val
+			return z, ErrRateLimited
+		}
+		return fn(ctx, k)
+	}
+}
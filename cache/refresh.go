@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+func refreshDue[Key comparable, Val any](e *cacheEntry[Key, Val], after time.Duration, now time.Time) bool {
+	return after > 0 && now.UnixNano()-e.written.Load() >= int64(after)
+}
+
+func (c *lruCache[Key, Val]) refresh(e *cacheEntry[Key, Val]) {
+	if e.refreshing.CompareAndSwap(false, true) {
+		go c.reload(e)
+	}
+}
+
+func (c *lruCache[Key, Val]) reload(e *cacheEntry[Key, Val]) {
+	defer e.refreshing.Store(false)
+
+	k, old := e.key, e.val.Load()
+	c.refreshes.do(context.Background(), k, func(ctx context.Context) (Val, error) {
+		start := c.clock.Now()
+		v, err := c.loader(ctx, k)
+		c.stats.loaded(c.clock.Now().Sub(start), err)
+		if err != nil {
+			c.logger.Debug("cache refresh failed", "key", k, "err", err)
+			return v, err
+		}
+
+		c.mu.Lock()
+		defer c.unlock()
+		if cur, ok := c.live(k); ok && cur == e && e.val.Load() == old {
+			c.replace(e, v)
+		}
+		return v, nil
+	})
+}
+
+func (c *ttlCache[Key, Val]) refreshAhead(now time.Time) {
+	c.store.Range(func(_, v any) bool {
+		if e := v.(*cacheEntry[Key, Val]); !c.expired(e) && refreshDue(e, c.refreshAfter, now) {
+			c.revalidate(e)
+		}
+		return true
+	})
+}
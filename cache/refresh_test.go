@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRURefreshDoesNotResurrectDeletedKey(t *testing.T) {
+	clock := newFakeClock()
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	loads := 0
+	c, err := NewLRU(10,
+		WithClock[string, int](clock),
+		WithRefreshAfter[string, int](time.Minute),
+		WithLoader(func(ctx context.Context, k string) (int, error) {
+			loads++
+			if loads > 1 {
+				started <- struct{}{}
+				<-release
+			}
+			return loads, nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get = %v, %v; want 1, true", v, ok)
+	}
+	c.mu.RLock()
+	e := c.store["a"]
+	c.mu.RUnlock()
+
+	clock.Advance(time.Minute)
+	c.Get("a")
+	<-started
+	c.Delete("a")
+	close(release)
+	for e.refreshing.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	if n := c.Len(); n != 0 {
+		t.Fatalf("Len = %d after delete; the refresh brought the key back", n)
+	}
+}
+
+func TestTTLRevalidateDoesNotResurrectDeletedKey(t *testing.T) {
+	clock := newFakeClock()
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	loads := 0
+	c, err := NewTTL(time.Hour, false,
+		WithClock[string, int](clock),
+		WithRefreshAfter[string, int](time.Minute),
+		WithLoader(func(ctx context.Context, k string) (int, error) {
+			loads++
+			if loads > 1 {
+				started <- struct{}{}
+				<-release
+			}
+			return loads, nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get = %v, %v; want 1, true", v, ok)
+	}
+	e, _ := c.entry("a")
+
+	clock.Advance(time.Minute)
+	c.Get("a")
+	<-started
+	c.Delete("a")
+	close(release)
+	for e.refreshing.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	if n := c.Len(); n != 0 {
+		t.Fatalf("Len = %d after delete; the revalidation brought the key back", n)
+	}
+}
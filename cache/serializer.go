@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+)
+
+type Serializer[Val any] interface {
+	Marshal(v Val) ([]byte, error)
+	Unmarshal(b []byte) (Val, error)
+}
+
+type codecSerializer[Val any] struct {
+	codec Codec
+	raw   bool
+}
+
+func NewSerializer[Val any](codec Codec) (Serializer[Val], error) {
+	if codec == nil {
+		return nil, fmt.Errorf("%w: codec must not be nil", ErrInvalidOption)
+	}
+	_, encrypted := codec.(encryptedCodec)
+	return codecSerializer[Val]{codec, !encrypted}, nil
+}
+
+func (s codecSerializer[Val]) Marshal(v Val) ([]byte, error) {
+	if s.raw {
+		switch x := any(v).(type) {
+		case []byte:
+			return x, nil
+		case string:
+			return []byte(x), nil
+		}
+	}
+	var buf bytes.Buffer
+	if err := s.codec.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s codecSerializer[Val]) Unmarshal(b []byte) (Val, error) {
+	var v Val
+	if s.raw {
+		switch p := any(&v).(type) {
+		case *[]byte:
+			*p = bytes.Clone(b)
+			return v, nil
+		case *string:
+			*p = string(b)
+			return v, nil
+		}
+	}
+	err := s.codec.NewDecoder(bytes.NewReader(b)).Decode(&v)
+	return v, err
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+type shardedCache[Key comparable, Val any] struct {
+	shards []Cache[Key, Val]
+	seed   maphash.Seed
+}
+
+func NewSharded[Key comparable, Val any](n int, newShard func() (Cache[Key, Val], error)) (*shardedCache[Key, Val], error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: shard count must be greater than zero", ErrInvalidOption)
+	}
+	c := &shardedCache[Key, Val]{
+		shards: make([]Cache[Key, Val], n),
+		seed:   maphash.MakeSeed(),
+	}
+	for i := range c.shards {
+		s, err := newShard()
+		if err != nil {
+			return nil, err
+		}
+		c.shards[i] = s
+	}
+	return c, nil
+}
+
+func (c *shardedCache[Key, Val]) Get(k Key) (Val, bool) {
+	return c.shard(k).Get(k)
+}
+
+func (c *shardedCache[Key, Val]) Put(k Key, v Val) {
+	c.shard(k).Put(k, v)
+}
+
+func (c *shardedCache[Key, Val]) Delete(k Key) bool {
+	return c.shard(k).Delete(k)
+}
+
+func (c *shardedCache[Key, Val]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+func (c *shardedCache[Key, Val]) Clear() {
+	for _, s := range c.shards {
+		s.Clear()
+	}
+}
+
+func (c *shardedCache[Key, Val]) Stats() Stats {
+	var total Stats
+	for _, s := range c.shards {
+		total = total.merge(s.Stats())
+	}
+	return total
+}
+
+func (c *shardedCache[Key, Val]) shard(k Key) Cache[Key, Val] {
+	return c.shards[maphash.Comparable(c.seed, k)%uint64(len(c.shards))]
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const loadTimeout = 30 * time.Second
+
+type flight[Val any] struct {
+	done     chan struct{}
+	val      Val
+	err      error
+	panicked any
+}
+
+type flightGroup[Key comparable, Val any] struct {
+	flights map[Key]*flight[Val]
+	mu      sync.Mutex
+}
+
+func (g *flightGroup[Key, Val]) do(ctx context.Context, k Key, fn func(ctx context.Context) (Val, error)) (Val, error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = make(map[Key]*flight[Val])
+	}
+	f, ok := g.flights[k]
+	if !ok {
+		f = &flight[Val]{done: make(chan struct{})}
+		g.flights[k] = f
+		go g.run(ctx, k, f, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.panicked != nil {
+			panic(f.panicked)
+		}
+		return f.val, f.err
+	case <-ctx.Done():
+		var z Val
+		return z, ctx.Err()
+	}
+}
+
+func (g *flightGroup[Key, Val]) run(ctx context.Context, k Key, f *flight[Val], fn func(ctx context.Context) (Val, error)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+	defer cancel()
+
+	defer func() {
+		f.panicked = recover()
+		g.mu.Lock()
+		delete(g.flights, k)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+	f.val, f.err = fn(ctx)
+}
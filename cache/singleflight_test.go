@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFlightLeaderCancelDoesNotFailWaiters(t *testing.T) {
+	var g flightGroup[string, int]
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	leader, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := g.do(leader, "k", func(ctx context.Context) (int, error) {
+			close(started)
+			select {
+			case <-release:
+				return 1, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		})
+		leaderErr <- err
+	}()
+	<-started
+
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("leader err = %v; want context.Canceled", err)
+	}
+
+	time.AfterFunc(20*time.Millisecond, func() { close(release) })
+	v, err := g.do(context.Background(), "k", func(context.Context) (int, error) {
+		return 2, nil
+	})
+	if err != nil || v != 1 {
+		t.Fatalf("waiter got %d, %v; want the leader's result 1", v, err)
+	}
+}
+
+func TestFlightPanicReachesWaiters(t *testing.T) {
+	var g flightGroup[string, int]
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Fatalf("recovered %v; want boom", r)
+		}
+	}()
+	g.do(context.Background(), "k", func(context.Context) (int, error) {
+		panic("boom")
+	})
+	t.Fatal("do returned after a panic")
+}
@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+const snapshotVersion = 1
+
+type snapshot[Key comparable, Val any] struct {
+	Version  int                       `json:"version"`
+	Capacity int                       `json:"capacity,omitempty"`
+	TTL      time.Duration             `json:"ttl,omitempty"`
+	Entries  []snapshotEntry[Key, Val] `json:"entries"`
+}
+
+type snapshotEntry[Key comparable, Val any] struct {
+	Key        Key       `json:"key"`
+	Val        Val       `json:"value"`
+	Created    time.Time `json:"created,omitzero"`
+	LastAccess time.Time `json:"last_access,omitzero"`
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`
+	Persistent bool      `json:"persistent,omitempty"`
+	Hits       uint64    `json:"hits,omitempty"`
+}
+
+func newSnapshotEntry[Key comparable, Val any](e *cacheEntry[Key, Val]) snapshotEntry[Key, Val] {
+	se := snapshotEntry[Key, Val]{
+		Key:        e.key,
+		Val:        e.value(),
+		Created:    time.Unix(0, e.created),
+		ExpiresAt:  e.expiresAt(),
+		Persistent: e.timeToLive() == 0,
+		Hits:       e.hits.Load(),
+	}
+	if t := e.lastAccess.Load(); t != 0 {
+		se.LastAccess = time.Unix(0, t)
+	}
+	return se
+}
+
+func (se snapshotEntry[Key, Val]) entry(ttl time.Duration, now time.Time) *cacheEntry[Key, Val] {
+	e := newCacheEntry(se.Key, se.Val, ttl, now)
+	if !se.Created.IsZero() {
+		e.created = se.Created.UnixNano()
+	}
+	e.hits.Store(se.Hits)
+	if !se.LastAccess.IsZero() {
+		e.lastAccess.Store(se.LastAccess.UnixNano())
+	}
+	return e
+}
+
+func decodeSnapshot[Key comparable, Val any](dec Decoder) (snapshot[Key, Val], error) {
+	var snap snapshot[Key, Val]
+	if err := dec.Decode(&snap); err != nil {
+		return snap, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	if snap.Version > snapshotVersion {
+		return snap, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, snap.Version)
+	}
+	return snap, nil
+}
+
+func (c *lruCache[Key, Val]) SaveTo(w io.Writer) error {
+	return c.codec.NewEncoder(w).Encode(c.snapshot())
+}
+
+func (c *lruCache[Key, Val]) snapshot() snapshot[Key, Val] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return snapshot[Key, Val]{
+		Version:  snapshotVersion,
+		Capacity: c.capacity,
+		Entries:  c.entries(),
+	}
+}
+
+func (c *lruCache[Key, Val]) entries() []snapshotEntry[Key, Val] {
+	entries := make([]snapshotEntry[Key, Val], 0, len(c.store))
+	it := c.order.Iterator()
+	for it.Next() {
+		if e := c.store[it.Value().(Key)]; !c.stale(e) {
+			entries = append(entries, newSnapshotEntry(e))
+		}
+	}
+	return entries
+}
+
+func NewLRUFromSnapshot[Key comparable, Val any](r io.Reader, opts ...Option[Key, Val]) (*lruCache[Key, Val], error) {
+	o := applyOptions(opts)
+	if o.codec == nil {
+		return nil, fmt.Errorf("%w: codec must not be nil", ErrInvalidOption)
+	}
+	snap, err := decodeSnapshot[Key, Val](o.codec.NewDecoder(r))
+	if err != nil {
+		return nil, err
+	}
+	if o.capacity == 0 {
+		o.capacity = snap.Capacity
+	}
+	c, err := newLRU(o)
+	if err != nil {
+		return nil, err
+	}
+	c.restore(snap.Entries)
+	return c, nil
+}
+
+func (c *lruCache[Key, Val]) restore(entries []snapshotEntry[Key, Val]) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	for _, se := range entries {
+		c.restoreEntry(se, now)
+	}
+}
+
+func (c *lruCache[Key, Val]) restoreEntry(se snapshotEntry[Key, Val], now time.Time) {
+	if _, ok := c.store[se.Key]; ok {
+		c.remove(se.Key)
+	}
+	c.link(se.entry(0, now))
+}
+
+func (c *ttlCache[Key, Val]) SaveTo(w io.Writer) error {
+	return c.codec.NewEncoder(w).Encode(c.snapshot())
+}
+
+func (c *ttlCache[Key, Val]) snapshot() snapshot[Key, Val] {
+	return snapshot[Key, Val]{
+		Version:  snapshotVersion,
+		Capacity: c.capacity,
+		TTL:      c.timeToLive,
+		Entries:  c.entries(),
+	}
+}
+
+func (c *ttlCache[Key, Val]) entries() []snapshotEntry[Key, Val] {
+	var entries []snapshotEntry[Key, Val]
+	c.store.Range(func(_, v any) bool {
+		if e := v.(*cacheEntry[Key, Val]); !c.expired(e) {
+			entries = append(entries, newSnapshotEntry(e))
+		}
+		return true
+	})
+	return entries
+}
+
+func NewTTLFromSnapshot[Key comparable, Val any](r io.Reader, opts ...Option[Key, Val]) (*ttlCache[Key, Val], error) {
+	o := applyOptions(opts)
+	if o.codec == nil {
+		return nil, fmt.Errorf("%w: codec must not be nil", ErrInvalidOption)
+	}
+	snap, err := decodeSnapshot[Key, Val](o.codec.NewDecoder(r))
+	if err != nil {
+		return nil, err
+	}
+	if o.ttl == 0 {
+		o.ttl = snap.TTL
+	}
+	if o.capacity == 0 {
+		o.capacity = snap.Capacity
+	}
+	c, err := newTTL(o)
+	if err != nil {
+		return nil, err
+	}
+	c.restore(snap.Entries)
+	return c, nil
+}
+
+func (c *ttlCache[Key, Val]) restore(entries []snapshotEntry[Key, Val]) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	for _, se := range entries {
+		c.restoreEntry(se, now)
+	}
+}
+
+func (c *ttlCache[Key, Val]) restoreEntry(se snapshotEntry[Key, Val], now time.Time) {
+	var ttl time.Duration
+	if !se.Persistent {
+		ttl = c.lifetime(c.timeToLive)
+	}
+	if !se.Persistent && !se.ExpiresAt.IsZero() {
+		remaining := se.ExpiresAt.Sub(now)
+		if remaining <= 0 {
+			return
+		}
+		ttl = max(ttl, remaining)
+	}
+	if old, ok := c.entry(se.Key); ok {
+		c.remove(old)
+	}
+	e := se.entry(ttl, now)
+	if ttl > 0 && !se.ExpiresAt.IsZero() {
+		e.visit(se.ExpiresAt.Add(-ttl))
+	}
+	c.link(e)
+}
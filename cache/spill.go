@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"container/list"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type SpillStore[Key comparable, Val any] interface {
+	Spill(k Key, v Val, expiresAt time.Time)
+	Promote(k Key) (Val, time.Time, bool)
+	Delete(k Key) bool
+	Clear()
+}
+
+type spillFile[Key comparable] struct {
+	key  Key
+	name string
+	size int64
+}
+
+type diskSpill[Key comparable, Val any] struct {
+	dir      string
+	maxBytes int64
+	ser      Serializer[Val]
+	mu       sync.Mutex
+	index    map[Key]*list.Element
+	order    *list.List
+	bytes    int64
+	seq      uint64
+}
+
+func NewDiskSpill[Key comparable, Val any](dir string, maxBytes int64, ser Serializer[Val]) (*diskSpill[Key, Val], error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("%w: spill size must be greater than zero", ErrInvalidOption)
+	}
+	if ser == nil {
+		return nil, fmt.Errorf("%w: serializer must not be nil", ErrInvalidOption)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	stale, err := filepath.Glob(filepath.Join(dir, "*.spill"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range stale {
+		os.Remove(name)
+	}
+	return &diskSpill[Key, Val]{
+		dir:      dir,
+		maxBytes: maxBytes,
+		ser:      ser,
+		index:    make(map[Key]*list.Element),
+		order:    list.New(),
+	}, nil
+}
+
+func (s *diskSpill[Key, Val]) Spill(k Key, v Val, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payload, err := s.ser.Marshal(v)
+	if err != nil || int64(len(payload))+8 > s.maxBytes {
+		return
+	}
+	var deadline int64
+	if !expiresAt.IsZero() {
+		deadline = expiresAt.UnixNano()
+	}
+	data := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(payload)), uint64(deadline))
+	data = append(data, payload...)
+
+	s.seq++
+	name := filepath.Join(s.dir, strconv.FormatUint(s.seq, 36)+".spill")
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		os.Remove(name)
+		return
+	}
+	size := int64(len(data))
+
+	if el, ok := s.index[k]; ok {
+		s.remove(el)
+	}
+	s.index[k] = s.order.PushBack(&spillFile[Key]{k, name, size})
+	s.bytes += size
+	for s.bytes > s.maxBytes {
+		s.remove(s.order.Front())
+	}
+}
+
+func (s *diskSpill[Key, Val]) Promote(k Key) (Val, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var z Val
+	el, ok := s.index[k]
+	if !ok {
+		return z, time.Time{}, false
+	}
+	sf := el.Value.(*spillFile[Key])
+	defer s.remove(el)
+
+	data, err := os.ReadFile(sf.name)
+	if err != nil || len(data) < 8 {
+		return z, time.Time{}, false
+	}
+	v, err := s.ser.Unmarshal(data[8:])
+	if err != nil {
+		return z, time.Time{}, false
+	}
+	var expiresAt time.Time
+	if deadline := int64(binary.BigEndian.Uint64(data)); deadline != 0 {
+		expiresAt = time.Unix(0, deadline)
+	}
+	return v, expiresAt, true
+}
+
+func (s *diskSpill[Key, Val]) Delete(k Key) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.index[k]; ok {
+		s.remove(el)
+		return true
+	}
+	return false
+}
+
+func (s *diskSpill[Key, Val]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.order.Len() > 0 {
+		s.remove(s.order.Front())
+	}
+}
+
+func (s *diskSpill[Key, Val]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.index)
+}
+
+func (s *diskSpill[Key, Val]) remove(el *list.Element) {
+	sf := s.order.Remove(el).(*spillFile[Key])
+	delete(s.index, sf.key)
+	s.bytes -= sf.size
+	os.Remove(sf.name)
+}
+
+func (c *lruCache[Key, Val]) promote(k Key) (Val, bool) {
+	v, _, ok := c.spill.Promote(k)
+	if !ok {
+		return v, false
+	}
+	return c.settle(k, v), true
+}
+
+func (c *ttlCache[Key, Val]) promote(k Key) (Val, bool) {
+	v, expiresAt, ok := c.spill.Promote(k)
+	if !ok {
+		return v, false
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	old, ok := c.entry(k)
+	if ok && !c.expired(old) {
+		return c.value(old), true
+	}
+	now := c.clock.Now()
+	var ttl time.Duration
+	if !expiresAt.IsZero() {
+		if ttl = expiresAt.Sub(now); ttl <= 0 {
+			var z Val
+			return z, false
+		}
+	}
+	if ok {
+		c.discard(old)
+	}
+	c.link(newCacheEntry(k, c.pack(v), ttl, now))
+	return v, true
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+func (c *ttlCache[Key, Val]) freshUntil(e *cacheEntry[Key, Val]) time.Time {
+	exp := e.expiresAt()
+	if exp.IsZero() {
+		return exp
+	}
+	return exp.Add(-c.maxStale)
+}
+
+func (c *ttlCache[Key, Val]) stale(e *cacheEntry[Key, Val], now time.Time) bool {
+	exp := c.freshUntil(e)
+	return !exp.IsZero() && !now.Before(exp)
+}
+
+func (c *ttlCache[Key, Val]) revalidate(e *cacheEntry[Key, Val]) {
+	if e.refreshing.CompareAndSwap(false, true) {
+		go c.reload(e)
+	}
+}
+
+func (c *ttlCache[Key, Val]) reload(e *cacheEntry[Key, Val]) {
+	defer e.refreshing.Store(false)
+
+	k, old := e.key, e.val.Load()
+	c.refreshes.do(context.Background(), k, func(ctx context.Context) (Val, error) {
+		start := c.clock.Now()
+		v, err := c.loader(ctx, k)
+		cost := c.clock.Now().Sub(start)
+		c.stats.loaded(cost, err)
+		if err != nil {
+			c.logger.Debug("cache revalidation failed", "key", k, "err", err)
+			return v, err
+		}
+
+		c.mu.Lock()
+		defer c.unlock()
+		if cur, ok := c.entry(k); ok && cur == e && e.gen == c.generation.Load() && e.val.Load() == old {
+			c.put(k, v, c.timeToLive)
+			c.recordCost(k, cost)
+		}
+		return v, nil
+	})
+}
+
+func (c *ttlCache[Key, Val]) early(e *cacheEntry[Key, Val], now time.Time) bool {
+	cost := e.cost.Load()
+	exp := c.freshUntil(e)
+	if cost <= 0 || exp.IsZero() {
+		return false
+	}
+	gap := -float64(cost) * c.xfetchBeta * math.Log(1-rand.Float64())
+	return !now.Add(time.Duration(gap)).Before(exp)
+}
+
+func (c *ttlCache[Key, Val]) recordCost(k Key, cost time.Duration) {
+	if e, ok := c.entry(k); ok {
+		e.cost.Store(int64(cost))
+	}
+}
+
+func (c *ttlCache[Key, Val]) settle(k Key, v Val) Val {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && !c.expired(e) {
+		if !c.staleOnError || !c.stale(e, c.clock.Now()) {
+			if cur, err := c.unpacked(e); err == nil {
+				return cur
+			}
+		}
+	}
+	c.put(k, v, c.timeToLive)
+	return v
+}
+
+func (c *ttlCache[Key, Val]) fallback(k Key) (Val, bool) {
+	if e, ok := c.entry(k); ok && c.staleOnError && !c.expired(e) {
+		return c.checked(e)
+	}
+	var z Val
+	return z, false
+}
@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Expirations   uint64
+	Loads         uint64
+	LoadFailures  uint64
+	LoadTime      Histogram
+	EvictionAge   Histogram
+	HitTTL        Histogram
+	DroppedEvents uint64
+	Size          int
+}
+
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type Histogram struct {
+	Bounds []time.Duration
+	Counts []uint64
+	Count  uint64
+	Sum    time.Duration
+}
+
+type statsCounter struct {
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	evictions     atomic.Uint64
+	expirations   atomic.Uint64
+	loads         atomic.Uint64
+	loadFailures  atomic.Uint64
+	loadTime      *durationHistogram
+	evictionAge   *durationHistogram
+	hitTTL        *durationHistogram
+	droppedEvents atomic.Uint64
+}
+
+func newStatsCounter(histograms bool) *statsCounter {
+	s := &statsCounter{loadTime: newDurationHistogram(loadBounds)}
+	if histograms {
+		s.evictionAge = newDurationHistogram(lifetimeBounds)
+		s.hitTTL = newDurationHistogram(lifetimeBounds)
+	}
+	return s
+}
+
+func (s *statsCounter) record(hit bool) {
+	if hit {
+		s.hits.Add(1)
+	} else {
+		s.misses.Add(1)
+	}
+}
+
+func (s *statsCounter) hit(expiry, now time.Time) {
+	if s.hitTTL != nil && !expiry.IsZero() {
+		s.hitTTL.observe(expiry.Sub(now))
+	}
+}
+
+func (s *statsCounter) evicted(reason EvictionReason, created int64, clock Clock) {
+	switch reason {
+	case EvictionCapacity:
+		s.evictions.Add(1)
+	case EvictionExpired:
+		s.expirations.Add(1)
+	}
+	if s.evictionAge != nil {
+		s.evictionAge.observe(clock.Now().Sub(time.Unix(0, created)))
+	}
+}
+
+func (s *statsCounter) loaded(d time.Duration, err error) {
+	s.loads.Add(1)
+	s.loadTime.observe(d)
+	if err != nil {
+		s.loadFailures.Add(1)
+	}
+}
+
+func (s *statsCounter) snapshot(size int) Stats {
+	return Stats{
+		Hits:          s.hits.Load(),
+		Misses:        s.misses.Load(),
+		Evictions:     s.evictions.Load(),
+		Expirations:   s.expirations.Load(),
+		Loads:         s.loads.Load(),
+		LoadFailures:  s.loadFailures.Load(),
+		LoadTime:      s.loadTime.snapshot(),
+		EvictionAge:   s.evictionAge.snapshot(),
+		HitTTL:        s.hitTTL.snapshot(),
+		DroppedEvents: s.droppedEvents.Load(),
+		Size:          size,
+	}
+}
+
+func (s Stats) merge(o Stats) Stats {
+	s.Hits += o.Hits
+	s.Misses += o.Misses
+	s.Evictions += o.Evictions
+	s.Expirations += o.Expirations
+	s.Loads += o.Loads
+	s.LoadFailures += o.LoadFailures
+	s.LoadTime = s.LoadTime.merge(o.LoadTime)
+	s.EvictionAge = s.EvictionAge.merge(o.EvictionAge)
+	s.HitTTL = s.HitTTL.merge(o.HitTTL)
+	s.DroppedEvents += o.DroppedEvents
+	s.Size += o.Size
+	return s
+}
+
+var loadBounds = []time.Duration{
+	100 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+var lifetimeBounds = []time.Duration{
+	time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+}
+
+type durationHistogram struct {
+	bounds []time.Duration
+	counts []atomic.Uint64
+	count  atomic.Uint64
+	sum    atomic.Int64
+}
+
+func newDurationHistogram(bounds []time.Duration) *durationHistogram {
+	return &durationHistogram{
+		bounds: bounds,
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}
+}
+
+func (h *durationHistogram) observe(d time.Duration) {
+	i := 0
+	for i < len(h.bounds) && d > h.bounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+func (h *durationHistogram) snapshot() Histogram {
+	if h == nil {
+		return Histogram{}
+	}
+	counts := make([]uint64, len(h.counts))
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+	}
+	return Histogram{
+		Bounds: h.bounds,
+		Counts: counts,
+		Count:  h.count.Load(),
+		Sum:    time.Duration(h.sum.Load()),
+	}
+}
+
+func (h Histogram) merge(o Histogram) Histogram {
+	if h.Counts == nil {
+		return o
+	}
+	if o.Counts == nil {
+		return h
+	}
+	counts := make([]uint64, len(h.Counts))
+	for i := range counts {
+		counts[i] = h.Counts[i] + o.Counts[i]
+	}
+	return Histogram{
+		Bounds: h.Bounds,
+		Counts: counts,
+		Count:  h.Count + o.Count,
+		Sum:    h.Sum + o.Sum,
+	}
+}
@@ -0,0 +1,14 @@
+package cache
+
+import "context"
+
+type Store[Key comparable, Val any] interface {
+	Load(ctx context.Context, k Key) (Val, error)
+	Save(ctx context.Context, k Key, v Val) error
+	Delete(ctx context.Context, k Key) error
+}
+
+type BatchStore[Key comparable, Val any] interface {
+	Store[Key, Val]
+	SaveMany(ctx context.Context, entries map[Key]Val) error
+}
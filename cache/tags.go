@@ -0,0 +1,114 @@
+package cache
+
+type tagIndex[Key comparable] struct {
+	byTag map[string]map[Key]struct{}
+	byKey map[Key][]string
+}
+
+func (t *tagIndex[Key]) set(k Key, tags []string) {
+	t.remove(k)
+	if len(tags) == 0 {
+		return
+	}
+	if t.byTag == nil {
+		t.byTag = make(map[string]map[Key]struct{})
+		t.byKey = make(map[Key][]string)
+	}
+	for _, tag := range tags {
+		keys, ok := t.byTag[tag]
+		if !ok {
+			keys = make(map[Key]struct{})
+			t.byTag[tag] = keys
+		}
+		keys[k] = struct{}{}
+	}
+	t.byKey[k] = append([]string(nil), tags...)
+}
+
+func (t *tagIndex[Key]) remove(k Key) {
+	tags, ok := t.byKey[k]
+	if !ok {
+		return
+	}
+	for _, tag := range tags {
+		keys := t.byTag[tag]
+		delete(keys, k)
+		if len(keys) == 0 {
+			delete(t.byTag, tag)
+		}
+	}
+	delete(t.byKey, k)
+}
+
+func (t *tagIndex[Key]) keys(tag string) []Key {
+	keys := make([]Key, 0, len(t.byTag[tag]))
+	for k := range t.byTag[tag] {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (t *tagIndex[Key]) tags(k Key) []string {
+	return append([]string(nil), t.byKey[k]...)
+}
+
+func (t *tagIndex[Key]) clear() {
+	t.byTag = nil
+	t.byKey = nil
+}
+
+func (c *lruCache[Key, Val]) PutTagged(k Key, v Val, tags ...string) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.put(k, v)
+	if _, ok := c.store[k]; ok {
+		c.tags.set(k, tags)
+	}
+}
+
+func (c *lruCache[Key, Val]) Tags(k Key) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tags.tags(k)
+}
+
+func (c *lruCache[Key, Val]) InvalidateTag(tag string) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	keys := c.tags.keys(tag)
+	for _, k := range keys {
+		c.drop(k)
+	}
+	return len(keys)
+}
+
+func (c *ttlCache[Key, Val]) PutTagged(k Key, v Val, tags ...string) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.put(k, v, c.timeToLive)
+	if _, ok := c.entry(k); ok {
+		c.tags.set(k, tags)
+	}
+}
+
+func (c *ttlCache[Key, Val]) Tags(k Key) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tags.tags(k)
+}
+
+func (c *ttlCache[Key, Val]) InvalidateTag(tag string) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	n := 0
+	for _, k := range c.tags.keys(tag) {
+		if e, ok := c.entry(k); ok && c.discard(e) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+type ttlPutter[Key comparable, Val any] interface {
+	PutWithTTL(k Key, v Val, d time.Duration)
+}
+
+type tieredCache[Key comparable, Val any] struct {
+	l1     Cache[Key, Val]
+	l2     Cache[Key, Val]
+	l1TTL  time.Duration
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewTiered[Key comparable, Val any](l1, l2 Cache[Key, Val], l1TTL time.Duration) (*tieredCache[Key, Val], error) {
+	if l1 == nil || l2 == nil {
+		return nil, fmt.Errorf("%w: both tiers must be set", ErrInvalidOption)
+	}
+	if l1TTL < 0 {
+		return nil, ErrInvalidTTL
+	}
+	if _, ok := l1.(ttlPutter[Key, Val]); l1TTL > 0 && !ok {
+		return nil, fmt.Errorf("%w: l1 does not support per-entry ttl", ErrInvalidOption)
+	}
+	return &tieredCache[Key, Val]{l1: l1, l2: l2, l1TTL: l1TTL}, nil
+}
+
+func (c *tieredCache[Key, Val]) Get(k Key) (Val, bool) {
+	if v, ok := c.l1.Get(k); ok {
+		c.hits.Add(1)
+		return v, true
+	}
+	v, ok := c.l2.Get(k)
+	if !ok {
+		c.misses.Add(1)
+		return v, false
+	}
+	c.hits.Add(1)
+	c.putL1(k, v)
+	return v, true
+}
+
+func (c *tieredCache[Key, Val]) Put(k Key, v Val) {
+	c.l2.Put(k, v)
+	c.putL1(k, v)
+}
+
+func (c *tieredCache[Key, Val]) Delete(k Key) bool {
+	deleted := c.l2.Delete(k)
+	return c.l1.Delete(k) || deleted
+}
+
+func (c *tieredCache[Key, Val]) Len() int {
+	return c.l2.Len()
+}
+
+func (c *tieredCache[Key, Val]) Clear() {
+	c.l2.Clear()
+	c.l1.Clear()
+}
+
+func (c *tieredCache[Key, Val]) Stats() Stats {
+	l2 := c.l2.Stats()
+	s := c.l1.Stats().merge(l2)
+	s.Hits = c.hits.Load()
+	s.Misses = c.misses.Load()
+	s.Size = l2.Size
+	return s
+}
+
+func (c *tieredCache[Key, Val]) putL1(k Key, v Val) {
+	if c.l1TTL > 0 {
+		c.l1.(ttlPutter[Key, Val]).PutWithTTL(k, v, c.l1TTL)
+		return
+	}
+	c.l1.Put(k, v)
+}
@@ -2,96 +2,984 @@ package cache
 
 import (
 	"context"
+	"crypto/aes"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math/rand/v2"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type cacheEntry[Key comparable, Val any] struct {
-	key         Key
-	val         Val
-	lastVisited time.Time
-}
-
 type ttlCache[Key comparable, Val any] struct {
-	store         map[Key]*cacheEntry[Key, Val]
+	store         sync.Map
+	size          int
+	expiries      expiryQueue[Key, Val]
 	timeToLive    time.Duration
 	resetOnAccess bool
-	mu            sync.Mutex
+	resetOnWrite  bool
+	capacity      int
+	weigher       func(k Key, v Val) int64
+	weight        int64
+	maxWeight     int64
+	sizer         func(k Key, v Val) int64
+	doorkeeper    *bloomFilter[Key]
+	clock         Clock
+	logger        *slog.Logger
+	codec         Codec
+	jitter        float64
+	batchSize     int
+	loader        LoaderFunc[Key, Val]
+	maxStale      time.Duration
+	staleOnError  bool
+	xfetchBeta    float64
+	refreshAfter  time.Duration
+	maxLifetime   time.Duration
+	maxIdle       time.Duration
+	refreshes     flightGroup[Key, Val]
+	negative      *negativeCache[Key]
+	warmWorkers   int
+	equal         func(a, b Val) bool
+	onEvict       func(k Key, v Val, reason EvictionReason)
+	spill         SpillStore[Key, Val]
+	packer        func(v Val) Val
+	unpacker      func(v Val) (Val, error)
+	evicted       []evictedEntry[Key, Val]
+	events        chan Event[Key, Val]
+	flights       flightGroup[Key, Val]
+	tags          tagIndex[Key]
+	prefixes      *prefixIndex
+	priorities    priorityLevels
+	keyLocks      stripedLocks[Key]
+	generation    atomic.Uint64
+	batches       atomic.Uint64
+	staleCount    int
+	stats         *statsCounter
+	stopJanitor   context.CancelFunc
+	janitorDone   chan struct{}
+	snapshotPath  string
+	stopSnapshot  context.CancelFunc
+	snapshotDone  chan struct{}
+	aof           *appendLog[Key, Val]
+	stopLog       context.CancelFunc
+	logDone       chan struct{}
+	closed        atomic.Bool
+	mu            locker
 }
 
-func NewTTL[Key comparable, Val any](ttl time.Duration, roa bool) (*ttlCache[Key, Val], error) {
-	if ttl <= 0 {
-		return nil, fmt.Errorf("ttl must be greater than zero.")
+func NewTTL[Key comparable, Val any](ttl time.Duration, roa bool, opts ...Option[Key, Val]) (*ttlCache[Key, Val], error) {
+	o := applyOptions(opts)
+	o.ttl = ttl
+	o.resetOnRead = o.resetOnRead || roa
+	return newTTL(o)
+}
+
+func newTTL[Key comparable, Val any](o options[Key, Val]) (*ttlCache[Key, Val], error) {
+	if o.ttl <= 0 {
+		return nil, ErrInvalidTTL
+	}
+	if o.capacity < 0 {
+		return nil, ErrInvalidCapacity
+	}
+	if o.clock == nil {
+		return nil, fmt.Errorf("%w: clock must not be nil", ErrInvalidOption)
+	}
+	if err := o.wrapLoader(); err != nil {
+		return nil, err
+	}
+	if o.logger == nil {
+		return nil, fmt.Errorf("%w: logger must not be nil", ErrInvalidOption)
+	}
+	if o.codec == nil {
+		return nil, fmt.Errorf("%w: codec must not be nil", ErrInvalidOption)
+	}
+	if o.encryptionKey != nil {
+		if _, err := aes.NewCipher(o.encryptionKey); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidOption, err)
+		}
+	}
+	if o.snapshotPath != "" && o.snapshotInterval <= 0 {
+		return nil, fmt.Errorf("%w: snapshot interval must be greater than zero", ErrInvalidOption)
+	}
+	if o.noLocking && o.snapshotPath != "" {
+		return nil, fmt.Errorf("%w: auto snapshot requires locking", ErrInvalidOption)
+	}
+	if o.noLocking && o.logPath != "" {
+		return nil, fmt.Errorf("%w: append log requires locking", ErrInvalidOption)
+	}
+	if o.jitter < 0 || o.jitter >= 1 {
+		return nil, fmt.Errorf("%w: jitter must be in the range [0, 1)", ErrInvalidOption)
+	}
+
+	var expiries expiryQueue[Key, Val] = &expiryHeap[Key, Val]{}
+	if o.wheelTick != 0 || o.wheelSizes != nil {
+		if o.wheelTick <= 0 {
+			return nil, fmt.Errorf("%w: timing wheel tick must be greater than zero", ErrInvalidOption)
+		}
+		sizes := o.wheelSizes
+		if len(sizes) == 0 {
+			sizes = []int{256, 64, 64, 64}
+		}
+		for _, n := range sizes {
+			if n <= 0 {
+				return nil, fmt.Errorf("%w: timing wheel sizes must be greater than zero", ErrInvalidOption)
+			}
+		}
+		expiries = newTimingWheel[Key, Val](o.wheelTick, sizes, o.clock.Now())
+	}
+
+	basis := o.expiration
+	if basis == 0 {
+		basis = ExpireAfterWrite
+		if o.resetOnRead {
+			basis = ExpireAfterAccess
+		}
+	}
+	if o.janitor < 0 {
+		return nil, fmt.Errorf("%w: janitor interval must be greater than zero", ErrInvalidOption)
+	}
+	if o.noLocking && o.janitor > 0 {
+		return nil, fmt.Errorf("%w: janitor requires locking", ErrInvalidOption)
+	}
+	if o.maxWeight < 0 {
+		return nil, fmt.Errorf("%w: max weight must be greater than zero", ErrInvalidOption)
+	}
+	if o.doorkeeper < 0 {
+		return nil, fmt.Errorf("%w: doorkeeper size must be greater than zero", ErrInvalidOption)
+	}
+	if o.eventBuf < 0 {
+		return nil, fmt.Errorf("%w: event buffer size must be greater than zero", ErrInvalidOption)
+	}
+	if o.warmWorkers < 0 {
+		return nil, fmt.Errorf("%w: warm concurrency must be greater than zero", ErrInvalidOption)
+	}
+	if o.noLocking {
+		o.warmWorkers = 1
+	}
+	if o.maxStale < 0 {
+		return nil, fmt.Errorf("%w: max stale must be greater than zero", ErrInvalidOption)
+	}
+	if o.maxStale > 0 && o.loader == nil {
+		return nil, fmt.Errorf("%w: stale-while-revalidate requires a loader", ErrInvalidOption)
+	}
+	if o.softTTL != 0 {
+		switch {
+		case o.softTTL < 0 || o.softTTL >= o.ttl:
+			return nil, fmt.Errorf("%w: soft ttl must be between zero and the hard ttl", ErrInvalidOption)
+		case o.loader == nil:
+			return nil, fmt.Errorf("%w: soft ttl requires a loader", ErrInvalidOption)
+		case o.maxStale > 0:
+			return nil, fmt.Errorf("%w: soft ttl cannot be combined with stale-while-revalidate", ErrInvalidOption)
+		}
+		o.maxStale = o.ttl - o.softTTL
+		o.ttl = o.softTTL
+	}
+	if o.xfetchBeta < 0 {
+		return nil, fmt.Errorf("%w: xfetch beta must be greater than zero", ErrInvalidOption)
+	}
+	if o.xfetchBeta > 0 && o.loader == nil {
+		return nil, fmt.Errorf("%w: xfetch requires a loader", ErrInvalidOption)
+	}
+	if o.refreshAfter < 0 {
+		return nil, fmt.Errorf("%w: refresh interval must be greater than zero", ErrInvalidOption)
+	}
+	if o.refreshAfter > 0 && o.loader == nil {
+		return nil, fmt.Errorf("%w: refresh-ahead requires a loader", ErrInvalidOption)
+	}
+	if o.maxLifetime < 0 {
+		return nil, fmt.Errorf("%w: max lifetime must be greater than zero", ErrInvalidOption)
+	}
+	if o.maxIdle < 0 {
+		return nil, fmt.Errorf("%w: max idle must be greater than zero", ErrInvalidOption)
 	}
 
-	return &ttlCache[Key, Val]{
-		store:         make(map[Key]*cacheEntry[Key, Val]),
-		timeToLive:    ttl,
-		resetOnAccess: roa,
-	}, nil
+	c := &ttlCache[Key, Val]{
+		expiries:      expiries,
+		clock:         o.clock,
+		logger:        o.logger,
+		codec:         o.codec,
+		timeToLive:    o.ttl,
+		resetOnAccess: basis == ExpireAfterAccess,
+		resetOnWrite:  basis != ExpireAfterCreate,
+		capacity:      o.capacity,
+		weigher:       o.weigher,
+		maxWeight:     o.maxWeight,
+		sizer:         o.sizer,
+		jitter:        o.jitter,
+		batchSize:     o.batchSize,
+		loader:        o.loader,
+		maxStale:      o.maxStale,
+		staleOnError:  o.softTTL > 0,
+		xfetchBeta:    o.xfetchBeta,
+		refreshAfter:  o.refreshAfter,
+		maxLifetime:   o.maxLifetime,
+		maxIdle:       o.maxIdle,
+		warmWorkers:   o.warmWorkers,
+		equal:         o.equal,
+		onEvict:       o.onEvict,
+		spill:         o.spill,
+		packer:        o.pack,
+		unpacker:      o.unpack,
+		stats:         newStatsCounter(o.histograms),
+		mu:            newLocker(o.noLocking),
+	}
+	if o.doorkeeper > 0 {
+		c.doorkeeper = newBloomFilter[Key](o.doorkeeper, 0.01)
+	}
+	if o.negativeTTL < 0 {
+		return nil, ErrInvalidTTL
+	}
+	if o.negativeTTL > 0 {
+		neg, err := newNegativeCache[Key](o.negativeTTL, o.clock)
+		if err != nil {
+			return nil, err
+		}
+		c.negative = neg
+	}
+	if o.prefixIndex {
+		idx, err := newPrefixIndex[Key]()
+		if err != nil {
+			return nil, err
+		}
+		c.prefixes = idx
+	}
+	if o.eventBuf > 0 {
+		c.events = make(chan Event[Key, Val], o.eventBuf)
+	}
+	if o.snapshotPath != "" {
+		entries, err := readSnapshotFile[Key, Val](o.snapshotPath, o.codec)
+		if err != nil {
+			return nil, err
+		}
+		c.restore(entries)
+	}
+	if o.logPath != "" {
+		if err := c.openLog(o.logPath); err != nil {
+			return nil, err
+		}
+	}
+	if o.snapshotPath != "" {
+		var ctx context.Context
+		ctx, c.stopSnapshot = context.WithCancel(context.Background())
+		c.snapshotPath = o.snapshotPath
+		c.snapshotDone = make(chan struct{})
+		go autoSnapshot(ctx, o.snapshotPath, o.snapshotInterval, c, c.clock, c.logger, c.snapshotDone)
+	}
+	if o.janitor > 0 {
+		var ctx context.Context
+		ctx, c.stopJanitor = context.WithCancel(context.Background())
+		c.janitorDone = make(chan struct{})
+		go c.janitor(ctx, o.janitor, c.janitorDone)
+	}
+	return c, nil
 }
 
 func (c *ttlCache[Key, Val]) Get(k Key) (Val, bool) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	v, ok := c.lookup(k)
+	if !ok && c.spill != nil {
+		v, ok = c.promote(k)
+	}
+	c.stats.record(ok)
+	if ok || c.loader == nil {
+		return v, ok
+	}
+	v, err := c.load(context.Background(), k)
+	return v, err == nil
+}
 
-	if e, ok := c.store[k]; ok {
-		if c.resetOnAccess {
-			e.lastVisited = time.Now()
+func (c *ttlCache[Key, Val]) GetContext(ctx context.Context, k Key) (Val, error) {
+	v, ok := c.lookup(k)
+	if !ok && c.spill != nil {
+		v, ok = c.promote(k)
+	}
+	c.stats.record(ok)
+	if ok {
+		return v, nil
+	}
+	if c.loader == nil {
+		var z Val
+		return z, ErrNotFound
+	}
+	return c.load(ctx, k)
+}
+
+func (c *ttlCache[Key, Val]) lookup(k Key) (Val, bool) {
+	c.settled()
+	if e, ok := c.entry(k); ok {
+		if !c.expired(e) {
+			now := c.clock.Now()
+			if c.staleOnError && c.stale(e, now) {
+				var z Val
+				return z, false
+			}
+			e.access(now)
+			c.stats.hit(e.expiresAt(), now)
+			if c.resetOnAccess {
+				e.visit(now)
+			}
+			if (c.maxStale > 0 && c.stale(e, now)) || (c.xfetchBeta > 0 && c.early(e, now)) || refreshDue(e, c.refreshAfter, now) {
+				c.revalidate(e)
+			}
+			return c.checked(e)
+		}
+
+		c.mu.Lock()
+		if cur, ok := c.entry(k); ok && cur == e && c.expired(e) {
+			c.expire(e)
 		}
-		return e.val, true
+		c.unlock()
 	}
 	var z Val
 	return z, false
 }
 
+func (c *ttlCache[Key, Val]) GetWithExpiry(k Key) (Val, time.Time, bool) {
+	if e, ok := c.entry(k); ok && !c.expired(e) {
+		if v, ok := c.checked(e); ok {
+			c.stats.record(true)
+			now := c.clock.Now()
+			e.access(now)
+			c.stats.hit(e.expiresAt(), now)
+			if c.resetOnAccess {
+				e.visit(now)
+			}
+			return v, c.freshUntil(e), true
+		}
+	}
+	c.stats.record(false)
+	var z Val
+	return z, time.Time{}, false
+}
+
 func (c *ttlCache[Key, Val]) Put(k Key, v Val) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
+	c.put(k, v, c.timeToLive)
+}
+
+func (c *ttlCache[Key, Val]) PutWithTTL(k Key, v Val, d time.Duration) {
+	if d <= 0 {
+		d = c.timeToLive
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+	c.put(k, v, d)
+}
+
+func (c *ttlCache[Key, Val]) Add(k Key, v Val) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && !c.expired(e) {
+		return false
+	}
+	return c.insert(k, v, c.timeToLive)
+}
+
+func (c *ttlCache[Key, Val]) GetOrCompute(k Key, fn func() Val) Val {
+	if v, ok := c.lookup(k); ok {
+		return v
+	}
+	v, _ := c.flights.do(context.Background(), k, func(context.Context) (Val, error) {
+		v := fn()
+
+		c.mu.Lock()
+		defer c.unlock()
+
+		if e, ok := c.entry(k); ok && !c.expired(e) {
+			cur, err := c.unpacked(e)
+			if err == nil {
+				return cur, nil
+			}
+			c.corrupt(e, err)
+		}
+		c.insert(k, v, c.timeToLive)
+		return v, nil
+	})
+	return v
+}
+
+func (c *ttlCache[Key, Val]) Delete(k Key) bool {
+	c.negative.forget(k)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && c.discard(e) {
+		return true
+	}
+	return c.spill != nil && c.spill.Delete(k)
+}
+
+func (c *ttlCache[Key, Val]) GetMany(keys []Key) map[Key]Val {
+	found := make(map[Key]Val, len(keys))
+	for {
+		seq := c.settled()
+		for _, k := range keys {
+			if v, ok := c.lookup(k); ok {
+				found[k] = v
+			}
+		}
+		if c.batches.Load() == seq {
+			break
+		}
+		clear(found)
+	}
+	for _, k := range keys {
+		_, ok := found[k]
+		c.stats.record(ok)
+	}
+	return found
+}
+
+func (c *ttlCache[Key, Val]) PutMany(entries map[Key]Val) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.batches.Add(1)
+	for k, v := range entries {
+		c.put(k, v, c.timeToLive)
+	}
+	c.batches.Add(1)
+}
+
+func (c *ttlCache[Key, Val]) settled() uint64 {
+	seq := c.batches.Load()
+	if _, ok := c.mu.(noLock); ok {
+		return seq
+	}
+	for seq&1 == 1 {
+		c.mu.RLock()
+		c.mu.RUnlock()
+		seq = c.batches.Load()
+	}
+	return seq
+}
+
+func (c *ttlCache[Key, Val]) DeleteMany(keys []Key) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	n := 0
+	for _, k := range keys {
+		if e, ok := c.entry(k); ok && c.discard(e) {
+			n++
+		} else if c.spill != nil && c.spill.Delete(k) {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *ttlCache[Key, Val]) Pop(k Key) (Val, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && c.discard(e) {
+		v, err := c.unpacked(e)
+		return v, err == nil
+	}
+	var z Val
+	return z, false
+}
+
+func (c *ttlCache[Key, Val]) CompareAndSwap(k Key, old, new Val) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && !c.expired(e) && c.equal(c.value(e), old) {
+		if c.replace(e, new) {
+			if c.resetOnWrite {
+				c.visit(e)
+			}
+			c.aof.append(logPut, e)
+		}
+		return true
+	}
+	return false
+}
+
+func (c *ttlCache[Key, Val]) CompareAndDelete(k Key, old Val) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && !c.expired(e) && c.equal(c.value(e), old) {
+		c.drop(e)
+		return true
+	}
+	return false
+}
+
+func (c *ttlCache[Key, Val]) Update(k Key, fn func(old Val, exists bool) (Val, bool)) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	var old Val
+	e, ok := c.entry(k)
+	exists := ok && !c.expired(e)
+	if exists {
+		old = c.value(e)
+	}
+	v, keep := fn(old, exists)
+	switch {
+	case keep && exists:
+		if c.replace(e, v) {
+			if c.resetOnWrite {
+				c.visit(e)
+			}
+			c.aof.append(logPut, e)
+		}
+	case keep:
+		c.insert(k, v, c.timeToLive)
+	case ok:
+		c.discard(e)
+	}
+}
+
+func (c *ttlCache[Key, Val]) load(ctx context.Context, k Key) (Val, error) {
+	if err, ok := c.negative.lookup(k); ok {
+		if v, ok := c.fallback(k); ok {
+			return v, nil
+		}
+		var z Val
+		return z, err
+	}
+	return c.flights.do(ctx, k, func(ctx context.Context) (Val, error) {
+		start := c.clock.Now()
+		v, err := c.loader(ctx, k)
+		cost := c.clock.Now().Sub(start)
+		c.stats.loaded(cost, err)
+		if err != nil {
+			c.logger.Debug("cache load failed", "key", k, "err", err)
+			c.negative.record(k, err)
+			if v, ok := c.fallback(k); ok {
+				return v, nil
+			}
+			var z Val
+			return z, err
+		}
+		v = c.settle(k, v)
+		c.recordCost(k, cost)
+		return v, nil
+	})
+}
 
-	if e, ok := c.store[k]; ok {
-		e.lastVisited = time.Now()
-		e.val = v
+func (c *ttlCache[Key, Val]) entry(k Key) (*cacheEntry[Key, Val], bool) {
+	if e, ok := c.store.Load(k); ok {
+		return e.(*cacheEntry[Key, Val]), true
+	}
+	return nil, false
+}
+
+func (c *ttlCache[Key, Val]) put(k Key, v Val, ttl time.Duration) {
+	if e, ok := c.entry(k); ok && !c.expired(e) {
+		if !c.replace(e, v) {
+			return
+		}
+		if c.resetOnWrite {
+			e.visit(c.clock.Now())
+		}
+		e.setTimeToLive(c.lifetime(ttl))
+		c.expiries.schedule(e)
+		c.aof.append(logPut, e)
 	} else {
-		e := &cacheEntry[Key, Val]{
-			key:         k,
-			val:         v,
-			lastVisited: time.Now(),
+		c.insert(k, v, ttl)
+	}
+}
+
+func (c *ttlCache[Key, Val]) insert(k Key, v Val, ttl time.Duration) bool {
+	return c.admit(k) && c.link(newCacheEntry(k, c.pack(v), c.lifetime(ttl), c.clock.Now()))
+}
+
+func (c *ttlCache[Key, Val]) admit(k Key) bool {
+	if old, ok := c.entry(k); ok {
+		c.discard(old)
+		return true
+	}
+	return c.doorkeeper == nil || c.doorkeeper.admit(k)
+}
+
+func (c *ttlCache[Key, Val]) link(e *cacheEntry[Key, Val]) bool {
+	w := c.weigh(e.key, e.value())
+	if c.maxWeight > 0 && w > c.maxWeight {
+		return false
+	}
+	n := 0
+	for (c.capacity > 0 && c.size >= c.capacity) || (c.maxWeight > 0 && c.weight+w > c.maxWeight) {
+		if !c.evict(nil) {
+			break
+		}
+		n++
+	}
+	logEvictions(c.logger, n)
+	e.weight = w
+	e.gen = c.generation.Load()
+	if c.maxLifetime > 0 {
+		e.expiresBy = e.created + int64(c.maxLifetime)
+	}
+	e.maxIdle = int64(c.maxIdle)
+	c.store.Store(e.key, e)
+	c.prefixes.add(e.key)
+	c.priorities.add(e.priority)
+	c.size++
+	c.weight += w
+	c.expiries.schedule(e)
+	c.emit(EventPut, e.key, c.value(e))
+	c.aof.append(logPut, e)
+	return true
+}
+
+func (c *ttlCache[Key, Val]) replace(e *cacheEntry[Key, Val], v Val) bool {
+	stored := c.pack(v)
+	w := c.weigh(e.key, stored)
+	if c.maxWeight > 0 && w > c.maxWeight {
+		c.notify(e, EvictionCapacity)
+		c.remove(e)
+		return false
+	}
+	c.weight += w - e.weight
+	e.weight = w
+	e.setValue(stored)
+	e.written.Store(c.clock.Now().UnixNano())
+	c.emit(EventPut, e.key, v)
+	if c.maxWeight > 0 && c.weight > c.maxWeight {
+		c.expiries.remove(e)
+		n := 0
+		for c.weight > c.maxWeight && c.evict(e) {
+			n++
+		}
+		logEvictions(c.logger, n)
+		c.expiries.schedule(e)
+	}
+	return true
+}
+
+func (c *ttlCache[Key, Val]) weigh(k Key, v Val) int64 {
+	if c.weigher == nil {
+		return 1
+	}
+	return c.weigher(k, v)
+}
+
+func (c *ttlCache[Key, Val]) lifetime(ttl time.Duration) time.Duration {
+	if ttl = c.jittered(ttl); ttl == 0 {
+		return 0
+	}
+	return ttl + c.maxStale
+}
+
+func (c *ttlCache[Key, Val]) jittered(ttl time.Duration) time.Duration {
+	if c.jitter == 0 || ttl == 0 {
+		return ttl
+	}
+	return ttl + time.Duration(float64(ttl)*c.jitter*(2*rand.Float64()-1))
+}
+
+func (c *ttlCache[Key, Val]) visit(e *cacheEntry[Key, Val]) {
+	e.visit(c.clock.Now())
+	c.expiries.schedule(e)
+}
+
+func (c *ttlCache[Key, Val]) discard(e *cacheEntry[Key, Val]) bool {
+	if c.expired(e) {
+		c.expire(e)
+		return false
+	}
+	c.drop(e)
+	return true
+}
+
+func (c *ttlCache[Key, Val]) expire(e *cacheEntry[Key, Val]) {
+	c.notify(e, EvictionExpired)
+	c.remove(e)
+}
+
+func (c *ttlCache[Key, Val]) notify(e *cacheEntry[Key, Val], reason EvictionReason) {
+	c.stats.evicted(reason, e.created, c.clock)
+	c.emit(evictionEvent(reason), e.key, c.value(e))
+	if c.onEvict != nil || c.spill != nil {
+		c.evicted = append(c.evicted, evictedEntry[Key, Val]{e.key, c.value(e), reason, e.expiresAt()})
+	}
+}
+
+func (c *ttlCache[Key, Val]) Events() <-chan Event[Key, Val] {
+	return c.events
+}
+
+func (c *ttlCache[Key, Val]) DroppedEvents() uint64 {
+	return c.stats.droppedEvents.Load()
+}
+
+func (c *ttlCache[Key, Val]) emit(t EventType, k Key, v Val) {
+	if c.events == nil {
+		return
+	}
+	select {
+	case c.events <- Event[Key, Val]{t, k, v}:
+	default:
+		c.stats.droppedEvents.Add(1)
+	}
+}
+
+func (c *ttlCache[Key, Val]) unlock() {
+	evicted := c.evicted
+	c.evicted = nil
+	if c.spill != nil {
+		for _, e := range evicted {
+			if e.reason == EvictionCapacity {
+				c.spill.Spill(e.key, e.val, e.expiresAt)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	if c.onEvict != nil {
+		for _, e := range evicted {
+			c.onEvict(e.key, e.val, e.reason)
+		}
+	}
+}
+
+func (c *ttlCache[Key, Val]) drop(e *cacheEntry[Key, Val]) {
+	if c.spill != nil {
+		c.spill.Delete(e.key)
+	}
+	c.emit(EventDelete, e.key, c.value(e))
+	c.aof.append(logDelete, e)
+	c.remove(e)
+}
+
+func (c *ttlCache[Key, Val]) remove(e *cacheEntry[Key, Val]) {
+	if c.store.CompareAndDelete(e.key, e) {
+		c.size--
+		c.weight -= e.weight
+		if e.gen != c.generation.Load() {
+			c.staleCount--
 		}
-		c.store[k] = e
+		c.tags.remove(e.key)
+		c.prefixes.remove(e.key)
+		c.priorities.remove(e.priority)
+	}
+	c.expiries.remove(e)
+}
+
+func (c *ttlCache[Key, Val]) Touch(k Key) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && !c.expired(e) {
+		c.visit(e)
+		c.aof.append(logPut, e)
+		return true
 	}
+	return false
+}
+
+func (c *ttlCache[Key, Val]) Expire(k Key, d time.Duration) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	e, ok := c.entry(k)
+	if !ok || c.expired(e) {
+		return false
+	}
+	if d <= 0 {
+		c.drop(e)
+		return true
+	}
+	e.visit(c.clock.Now())
+	e.setTimeToLive(d + c.maxStale)
+	c.expiries.schedule(e)
+	c.aof.append(logPut, e)
+	return true
+}
+
+func (c *ttlCache[Key, Val]) Persist(k Key) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if e, ok := c.entry(k); ok && !c.expired(e) {
+		e.setTimeToLive(0)
+		c.expiries.schedule(e)
+		c.aof.append(logPut, e)
+		return true
+	}
+	return false
 }
 
 func (c *ttlCache[Key, Val]) Size() int {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	return len(c.store)
+	defer c.unlock()
+
+	c.removeExpired(c.batchSize)
+	return c.size - c.staleCount
 }
 
-func (c *ttlCache[Key, Val]) Cleanup() {
+func (c *ttlCache[Key, Val]) Len() int {
+	return c.Size()
+}
+
+func (c *ttlCache[Key, Val]) Stats() Stats {
+	return c.stats.snapshot(c.Len())
+}
+
+func (c *ttlCache[Key, Val]) Clear() {
+	c.negative.clear()
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
+	c.clear()
+}
+
+func (c *ttlCache[Key, Val]) Cleanup() {
+	c.cleanup()
+}
+
+func (c *ttlCache[Key, Val]) cleanup() int {
+	total := 0
+	for {
+		c.mu.Lock()
+		n := c.removeExpired(c.batchSize)
+		c.unlock()
+
+		total += n
+		if c.batchSize <= 0 || n < c.batchSize {
+			return total
+		}
+	}
+}
 
-	for k, e := range c.store {
-		if time.Since(e.lastVisited) >= c.timeToLive {
-			delete(c.store, k)
+func (c *ttlCache[Key, Val]) removeExpired(limit int) int {
+	due := c.expiries.expire(c.clock.Now(), limit)
+	for _, e := range due {
+		if !e.pinned.Load() {
+			c.expire(e)
+		}
+	}
+	return len(due)
+}
+
+func (c *ttlCache[Key, Val]) evict(except *cacheEntry[Key, Val]) bool {
+	lowest := c.priorities.lowest()
+	for e := c.expiries.peek(); e != nil && e != except; e = c.expiries.peek() {
+		if !e.pinned.Load() {
+			if e.priority > lowest {
+				break
+			}
+			c.evictEntry(e)
+			return true
+		}
+		c.expiries.remove(e)
+	}
+	var victim *cacheEntry[Key, Val]
+	c.store.Range(func(_, v any) bool {
+		e := v.(*cacheEntry[Key, Val])
+		if e == except || (e.pinned.Load() && !c.expired(e)) {
+			return true
+		}
+		if victim == nil || e.priority < victim.priority || (e.priority == victim.priority && expiresBefore(e, victim)) {
+			victim = e
 		}
+		return c.priorities.mixed() || victim.priority > lowest
+	})
+	if victim == nil {
+		return false
+	}
+	c.evictEntry(victim)
+	return true
+}
+
+func (c *ttlCache[Key, Val]) evictEntry(e *cacheEntry[Key, Val]) {
+	if c.expired(e) {
+		c.expire(e)
+		return
 	}
+	c.notify(e, EvictionCapacity)
+	c.remove(e)
+}
+
+func (c *ttlCache[Key, Val]) expired(e *cacheEntry[Key, Val]) bool {
+	if e.gen != c.generation.Load() {
+		return true
+	}
+	return !e.pinned.Load() && e.timeToLive() > 0 && !c.clock.Now().Before(e.expiresAt())
 }
 
 func (c *ttlCache[Key, Val]) ScheduleCleanup(ctx context.Context, e time.Duration) {
-	go func() {
-		ticker := time.NewTicker(e)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				c.Cleanup()
-			case <-ctx.Done():
-				return
+	go c.janitor(ctx, e, nil)
+}
+
+func (c *ttlCache[Key, Val]) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	if c.stopJanitor != nil {
+		c.stopJanitor()
+		<-c.janitorDone
+	}
+	var err error
+	if c.stopSnapshot != nil {
+		c.stopSnapshot()
+		<-c.snapshotDone
+		err = saveSnapshotFile(c.snapshotPath, c, c.logger)
+	}
+	if c.stopLog != nil {
+		c.stopLog()
+		<-c.logDone
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+	if c.aof != nil {
+		err = errors.Join(err, c.aof.close())
+		c.aof = nil
+	}
+	return err
+}
+
+func (c *ttlCache[Key, Val]) clear() {
+	c.store.Clear()
+	c.size = 0
+	c.weight = 0
+	c.staleCount = 0
+	c.expiries.clear()
+	c.tags.clear()
+	c.prefixes.clear()
+	c.priorities.clear()
+
+	var (
+		k Key
+		v Val
+	)
+	c.emit(EventClear, k, v)
+	c.aof.append(logClear, nil)
+	if c.spill != nil {
+		c.spill.Clear()
+	}
+}
+
+func (c *ttlCache[Key, Val]) janitor(ctx context.Context, e time.Duration, done chan struct{}) {
+	if done != nil {
+		defer close(done)
+	}
+	ticker := c.clock.NewTicker(e)
+	defer ticker.Stop()
+
+	c.logger.Debug("cache janitor started", "interval", e)
+	for {
+		select {
+		case <-ticker.C():
+			start := c.clock.Now()
+			n := c.cleanup()
+			if c.refreshAfter > 0 {
+				c.refreshAhead(start)
 			}
+			c.logger.Debug("cache janitor run", "expired", n, "took", c.clock.Now().Sub(start))
+		case <-ctx.Done():
+			c.logger.Debug("cache janitor stopped", "err", ctx.Err())
+			return
 		}
-	}()
+	}
 }
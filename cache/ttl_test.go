@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLMaxIdle(t *testing.T) {
+	clock := newFakeClock()
+	c, err := NewTTL(5*time.Minute, false, WithClock[string, int](clock), WithMaxIdle[string, int](time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	c.Put("read", 1)
+	c.Put("idle", 2)
+	for range 5 {
+		clock.Advance(50 * time.Second)
+		if _, ok := c.Get("read"); !ok {
+			t.Fatal("entry read within the idle limit expired")
+		}
+	}
+	if _, ok := c.Get("idle"); ok {
+		t.Fatal("entry idle past the limit did not expire")
+	}
+
+	clock.Advance(50 * time.Second)
+	if _, ok := c.Get("read"); ok {
+		t.Fatal("entry outlived its ttl")
+	}
+}
@@ -0,0 +1,156 @@
+package cache
+
+type View[Key comparable, Val any] interface {
+	Get(k Key) (Val, bool)
+	Put(k Key, v Val)
+	Delete(k Key) bool
+}
+
+type txWrite[Val any] struct {
+	val     Val
+	deleted bool
+}
+
+type txView[Key comparable, Val any] struct {
+	read   func(k Key) (Val, bool)
+	writes map[Key]txWrite[Val]
+	order  []Key
+}
+
+func newTxView[Key comparable, Val any](read func(k Key) (Val, bool)) *txView[Key, Val] {
+	return &txView[Key, Val]{read: read, writes: make(map[Key]txWrite[Val])}
+}
+
+func (t *txView[Key, Val]) Get(k Key) (Val, bool) {
+	if w, ok := t.writes[k]; ok {
+		return w.val, !w.deleted
+	}
+	return t.read(k)
+}
+
+func (t *txView[Key, Val]) Put(k Key, v Val) {
+	t.stage(k, txWrite[Val]{val: v})
+}
+
+func (t *txView[Key, Val]) Delete(k Key) bool {
+	_, ok := t.Get(k)
+	t.stage(k, txWrite[Val]{deleted: true})
+	return ok
+}
+
+func (t *txView[Key, Val]) stage(k Key, w txWrite[Val]) {
+	if _, ok := t.writes[k]; !ok {
+		t.order = append(t.order, k)
+	}
+	t.writes[k] = w
+}
+
+func (c *lruCache[Key, Val]) GetAll(keys []Key) map[Key]Val {
+	return c.GetMany(keys)
+}
+
+func (c *lruCache[Key, Val]) PutAll(entries map[Key]Val) {
+	c.PutMany(entries)
+}
+
+func (c *lruCache[Key, Val]) Tx(fn func(view View[Key, Val]) error) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	view := newTxView(func(k Key) (Val, bool) {
+		e, ok := c.live(k)
+		if !ok {
+			var z Val
+			return z, false
+		}
+		v, err := c.unpacked(e)
+		if err != nil {
+			c.corrupt(e, err)
+			return v, false
+		}
+		e.access(c.clock.Now())
+		c.recentify(k)
+		return v, true
+	})
+	if err := fn(view); err != nil {
+		return err
+	}
+	for _, k := range view.order {
+		w := view.writes[k]
+		if !w.deleted {
+			c.put(k, w.val)
+			continue
+		}
+		c.negative.forget(k)
+		if _, ok := c.live(k); ok {
+			c.drop(k)
+		} else if c.spill != nil {
+			c.spill.Delete(k)
+		}
+	}
+	return nil
+}
+
+func (c *ttlCache[Key, Val]) GetAll(keys []Key) map[Key]Val {
+	c.mu.Lock()
+	defer c.unlock()
+
+	found := make(map[Key]Val, len(keys))
+	for _, k := range keys {
+		v, ok := c.read(k)
+		c.stats.record(ok)
+		if ok {
+			found[k] = v
+		}
+	}
+	return found
+}
+
+func (c *ttlCache[Key, Val]) PutAll(entries map[Key]Val) {
+	c.PutMany(entries)
+}
+
+func (c *ttlCache[Key, Val]) Tx(fn func(view View[Key, Val]) error) error {
+	c.mu.Lock()
+	defer c.unlock()
+
+	view := newTxView(c.read)
+	if err := fn(view); err != nil {
+		return err
+	}
+	c.batches.Add(1)
+	defer c.batches.Add(1)
+	for _, k := range view.order {
+		w := view.writes[k]
+		if !w.deleted {
+			c.put(k, w.val, c.timeToLive)
+			continue
+		}
+		c.negative.forget(k)
+		if e, ok := c.entry(k); ok {
+			c.discard(e)
+		} else if c.spill != nil {
+			c.spill.Delete(k)
+		}
+	}
+	return nil
+}
+
+func (c *ttlCache[Key, Val]) read(k Key) (Val, bool) {
+	e, ok := c.entry(k)
+	if !ok || c.expired(e) {
+		var z Val
+		return z, false
+	}
+	v, err := c.unpacked(e)
+	if err != nil {
+		c.corrupt(e, err)
+		return v, false
+	}
+	now := c.clock.Now()
+	e.access(now)
+	if c.resetOnAccess {
+		e.visit(now)
+	}
+	return v, true
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTTLPutAllIsAtomicForReaders(t *testing.T) {
+	c, err := NewTTL[string, int](time.Hour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	keys := make([]string, 64)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	batch := func(v int) map[string]int {
+		m := make(map[string]int, len(keys))
+		for _, k := range keys {
+			m[k] = v
+		}
+		return m
+	}
+	c.PutAll(batch(0))
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			c.PutAll(batch(i))
+		}
+	}()
+
+	for range 2000 {
+		got := c.GetMany(keys)
+		for _, k := range keys {
+			if got[k] != got[keys[0]] {
+				t.Fatalf("GetMany saw a partial batch: %s = %d, %s = %d", keys[0], got[keys[0]], k, got[k])
+			}
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
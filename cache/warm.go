@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+func warm[Key comparable, Val any](ctx context.Context, keys []Key, workers int, cached func(k Key) bool, load func(ctx context.Context, k Key) (Val, error)) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	sem := make(chan struct{}, workers)
+	for _, k := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+		if cached(k) {
+			<-sem
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if _, err := load(ctx, k); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (c *lruCache[Key, Val]) Warm(ctx context.Context, keys []Key) error {
+	if c.loader == nil {
+		return ErrNoLoader
+	}
+	return warm(ctx, keys, c.warmWorkers, c.cached, c.load)
+}
+
+func (c *lruCache[Key, Val]) WarmFrom(seq iter.Seq2[Key, Val]) int {
+	n := 0
+	for k, v := range seq {
+		c.Put(k, v)
+		n++
+	}
+	return n
+}
+
+func (c *lruCache[Key, Val]) cached(k Key) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.store[k]
+	return ok && !c.stale(e)
+}
+
+func (c *ttlCache[Key, Val]) Warm(ctx context.Context, keys []Key) error {
+	if c.loader == nil {
+		return ErrNoLoader
+	}
+	return warm(ctx, keys, c.warmWorkers, c.cached, c.load)
+}
+
+func (c *ttlCache[Key, Val]) WarmFrom(seq iter.Seq2[Key, Val]) int {
+	n := 0
+	for k, v := range seq {
+		c.Put(k, v)
+		n++
+	}
+	return n
+}
+
+func (c *ttlCache[Key, Val]) cached(k Key) bool {
+	e, ok := c.entry(k)
+	return ok && !c.expired(e)
+}
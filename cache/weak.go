@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"runtime"
+	"sync"
+	"weak"
+)
+
+var _ Cache[string, *any] = (*weakCache[string, any])(nil)
+
+type weakCache[Key comparable, Val any] struct {
+	store map[Key]weak.Pointer[Val]
+	stats *statsCounter
+	mu    sync.Mutex
+}
+
+type weakRef[Key comparable, Val any] struct {
+	key Key
+	ptr weak.Pointer[Val]
+}
+
+func NewWeak[Key comparable, Val any]() *weakCache[Key, Val] {
+	return &weakCache[Key, Val]{
+		store: make(map[Key]weak.Pointer[Val]),
+		stats: newStatsCounter(false),
+	}
+}
+
+func (c *weakCache[Key, Val]) Get(k Key) (*Val, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v := c.lookup(k)
+	c.stats.record(v != nil)
+	return v, v != nil
+}
+
+func (c *weakCache[Key, Val]) Put(k Key, v *Val) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v == nil {
+		delete(c.store, k)
+		return
+	}
+	c.put(k, v)
+}
+
+func (c *weakCache[Key, Val]) GetOrCompute(k Key, fn func() *Val) *Val {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if v := c.lookup(k); v != nil {
+		c.stats.record(true)
+		return v
+	}
+	c.stats.record(false)
+	v := fn()
+	if v != nil {
+		c.put(k, v)
+	}
+	return v
+}
+
+func (c *weakCache[Key, Val]) Delete(k Key) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v := c.lookup(k)
+	delete(c.store, k)
+	return v != nil
+}
+
+func (c *weakCache[Key, Val]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, p := range c.store {
+		if p.Value() != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *weakCache[Key, Val]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.store)
+}
+
+func (c *weakCache[Key, Val]) Stats() Stats {
+	return c.stats.snapshot(c.Len())
+}
+
+func (c *weakCache[Key, Val]) lookup(k Key) *Val {
+	p, ok := c.store[k]
+	if !ok {
+		return nil
+	}
+	v := p.Value()
+	if v == nil {
+		delete(c.store, k)
+	}
+	return v
+}
+
+func (c *weakCache[Key, Val]) put(k Key, v *Val) {
+	p := weak.Make(v)
+	c.store[k] = p
+	runtime.AddCleanup(v, c.collect, weakRef[Key, Val]{k, p})
+}
+
+func (c *weakCache[Key, Val]) collect(r weakRef[Key, Val]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, ok := c.store[r.key]; ok && p == r.ptr {
+		delete(c.store, r.key)
+		c.stats.evictions.Add(1)
+	}
+}
@@ -0,0 +1,134 @@
+package cache
+
+import "time"
+
+type timingWheel[Key comparable, Val any] struct {
+	tick    time.Duration
+	sizes   []int
+	offsets []int
+	spans   []int64
+	slots   []map[*cacheEntry[Key, Val]]struct{}
+	current int64
+}
+
+func newTimingWheel[Key comparable, Val any](tick time.Duration, sizes []int, now time.Time) *timingWheel[Key, Val] {
+	w := &timingWheel[Key, Val]{
+		tick:    tick,
+		sizes:   sizes,
+		offsets: make([]int, len(sizes)),
+		spans:   make([]int64, len(sizes)),
+		current: now.UnixNano() / int64(tick),
+	}
+	total, span := 0, int64(1)
+	for i, n := range sizes {
+		w.offsets[i] = total
+		total += n
+		span *= int64(n)
+		w.spans[i] = span
+	}
+	w.slots = make([]map[*cacheEntry[Key, Val]]struct{}, total)
+	return w
+}
+
+func (w *timingWheel[Key, Val]) schedule(e *cacheEntry[Key, Val]) {
+	w.remove(e)
+	if e.timeToLive() == 0 {
+		return
+	}
+	w.place(e)
+}
+
+func (w *timingWheel[Key, Val]) place(e *cacheEntry[Key, Val]) {
+	due := (e.expiresAt().UnixNano() + int64(w.tick) - 1) / int64(w.tick)
+	if due <= w.current {
+		due = w.current + 1
+	}
+	top := len(w.sizes) - 1
+	if due-w.current >= w.spans[top] {
+		due = w.current + w.spans[top] - 1
+	}
+
+	level := 0
+	for due-w.current >= w.spans[level] {
+		level++
+	}
+	granularity := int64(1)
+	if level > 0 {
+		granularity = w.spans[level-1]
+	}
+	slot := w.offsets[level] + int((due/granularity)%int64(w.sizes[level]))
+	if w.slots[slot] == nil {
+		w.slots[slot] = make(map[*cacheEntry[Key, Val]]struct{})
+	}
+	w.slots[slot][e] = struct{}{}
+	e.index = slot
+}
+
+func (w *timingWheel[Key, Val]) remove(e *cacheEntry[Key, Val]) {
+	if e.index >= 0 {
+		delete(w.slots[e.index], e)
+		e.index = -1
+	}
+}
+
+func (w *timingWheel[Key, Val]) expire(now time.Time, limit int) []*cacheEntry[Key, Val] {
+	var due []*cacheEntry[Key, Val]
+	target := now.UnixNano() / int64(w.tick)
+	for {
+		for e := range w.slots[int(w.current%int64(w.sizes[0]))] {
+			if limit > 0 && len(due) >= limit {
+				return due
+			}
+			w.remove(e)
+			if now.Before(e.expiresAt()) {
+				w.place(e)
+			} else {
+				due = append(due, e)
+			}
+		}
+		if w.current >= target {
+			return due
+		}
+		w.current++
+		for level := len(w.sizes) - 1; level > 0; level-- {
+			if w.current%w.spans[level-1] == 0 {
+				w.cascade(w.offsets[level] + int((w.current/w.spans[level-1])%int64(w.sizes[level])))
+			}
+		}
+	}
+}
+
+func (w *timingWheel[Key, Val]) cascade(slot int) {
+	entries := w.slots[slot]
+	w.slots[slot] = nil
+	for e := range entries {
+		e.index = -1
+		w.place(e)
+	}
+}
+
+func (w *timingWheel[Key, Val]) peek() *cacheEntry[Key, Val] {
+	for level, n := range w.sizes {
+		granularity := int64(1)
+		if level > 0 {
+			granularity = w.spans[level-1]
+		}
+		cursor := int((w.current / granularity) % int64(n))
+		for i := 0; i < n; i++ {
+			var soonest *cacheEntry[Key, Val]
+			for e := range w.slots[w.offsets[level]+(cursor+i)%n] {
+				if soonest == nil || e.expiresAt().Before(soonest.expiresAt()) {
+					soonest = e
+				}
+			}
+			if soonest != nil {
+				return soonest
+			}
+		}
+	}
+	return nil
+}
+
+func (w *timingWheel[Key, Val]) clear() {
+	w.slots = make([]map[*cacheEntry[Key, Val]]struct{}, len(w.slots))
+}
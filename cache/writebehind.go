@@ -0,0 +1,289 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+type WriteBehindOption[Key comparable, Val any] func(*writeBehindOptions[Key, Val])
+
+type writeBehindOptions[Key comparable, Val any] struct {
+	interval   time.Duration
+	batchSize  int
+	maxPending int
+	onError    func(k Key, err error)
+	clock      Clock
+}
+
+func WithFlushInterval[Key comparable, Val any](d time.Duration) WriteBehindOption[Key, Val] {
+	return func(o *writeBehindOptions[Key, Val]) {
+		o.interval = d
+	}
+}
+
+func WithFlushBatch[Key comparable, Val any](n int) WriteBehindOption[Key, Val] {
+	return func(o *writeBehindOptions[Key, Val]) {
+		o.batchSize = n
+	}
+}
+
+func WithMaxPending[Key comparable, Val any](n int) WriteBehindOption[Key, Val] {
+	return func(o *writeBehindOptions[Key, Val]) {
+		o.maxPending = n
+	}
+}
+
+func WithFlushError[Key comparable, Val any](fn func(k Key, err error)) WriteBehindOption[Key, Val] {
+	return func(o *writeBehindOptions[Key, Val]) {
+		o.onError = fn
+	}
+}
+
+func WithFlushClock[Key comparable, Val any](clock Clock) WriteBehindOption[Key, Val] {
+	return func(o *writeBehindOptions[Key, Val]) {
+		o.clock = clock
+	}
+}
+
+type pendingWrite[Val any] struct {
+	val     Val
+	deleted bool
+	seq     uint64
+}
+
+type writeBehindCache[Key comparable, Val any] struct {
+	Cache[Key, Val]
+	store      Store[Key, Val]
+	batchSize  int
+	maxPending int
+	onError    func(k Key, err error)
+	flights    flightGroup[Key, Val]
+	mu         sync.Mutex
+	dirty      map[Key]pendingWrite[Val]
+	seq        uint64
+	closed     bool
+	flushMu    sync.Mutex
+	clock      Clock
+	stop       context.CancelFunc
+	done       chan struct{}
+}
+
+func NewWriteBehind[Key comparable, Val any](c Cache[Key, Val], store Store[Key, Val], opts ...WriteBehindOption[Key, Val]) (*writeBehindCache[Key, Val], error) {
+	o := writeBehindOptions[Key, Val]{
+		interval:   time.Second,
+		batchSize:  100,
+		maxPending: 10000,
+		onError:    func(Key, error) {},
+		clock:      SystemClock,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", ErrInvalidOption)
+	}
+	if store == nil {
+		return nil, fmt.Errorf("%w: store must not be nil", ErrInvalidOption)
+	}
+	if o.interval <= 0 {
+		return nil, fmt.Errorf("%w: flush interval must be greater than zero", ErrInvalidOption)
+	}
+	if o.batchSize <= 0 {
+		return nil, fmt.Errorf("%w: flush batch size must be greater than zero", ErrInvalidOption)
+	}
+	if o.maxPending <= 0 {
+		return nil, fmt.Errorf("%w: max pending writes must be greater than zero", ErrInvalidOption)
+	}
+	if o.onError == nil {
+		return nil, fmt.Errorf("%w: flush error handler must not be nil", ErrInvalidOption)
+	}
+	if o.clock == nil {
+		return nil, fmt.Errorf("%w: clock must not be nil", ErrInvalidOption)
+	}
+	wb := &writeBehindCache[Key, Val]{
+		Cache:      c,
+		store:      store,
+		batchSize:  o.batchSize,
+		maxPending: o.maxPending,
+		onError:    o.onError,
+		clock:      o.clock,
+		dirty:      make(map[Key]pendingWrite[Val]),
+		done:       make(chan struct{}),
+	}
+	var ctx context.Context
+	ctx, wb.stop = context.WithCancel(context.Background())
+	go wb.run(ctx, o.interval)
+	return wb, nil
+}
+
+func (c *writeBehindCache[Key, Val]) Get(k Key) (Val, bool) {
+	v, err := c.GetContext(context.Background(), k)
+	return v, err == nil
+}
+
+func (c *writeBehindCache[Key, Val]) GetContext(ctx context.Context, k Key) (Val, error) {
+	if v, ok := c.Cache.Get(k); ok {
+		return v, nil
+	}
+	c.mu.Lock()
+	p, ok := c.dirty[k]
+	c.mu.Unlock()
+	if ok {
+		if p.deleted {
+			var z Val
+			return z, ErrNotFound
+		}
+		return p.val, nil
+	}
+	return c.flights.do(ctx, k, func(ctx context.Context) (Val, error) {
+		v, err := c.store.Load(ctx, k)
+		if err != nil {
+			return v, err
+		}
+		c.Cache.Put(k, v)
+		return v, nil
+	})
+}
+
+func (c *writeBehindCache[Key, Val]) Put(k Key, v Val) {
+	c.Cache.Put(k, v)
+	c.queue(k, pendingWrite[Val]{val: v})
+}
+
+func (c *writeBehindCache[Key, Val]) Delete(k Key) bool {
+	deleted := c.Cache.Delete(k)
+	c.queue(k, pendingWrite[Val]{deleted: true})
+	return deleted
+}
+
+func (c *writeBehindCache[Key, Val]) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.dirty)
+}
+
+func (c *writeBehindCache[Key, Val]) queue(k Key, p pendingWrite[Val]) {
+	for flushed := false; ; flushed = true {
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			c.write(k, p)
+			return
+		}
+		if _, ok := c.dirty[k]; ok || len(c.dirty) < c.maxPending {
+			c.seq++
+			p.seq = c.seq
+			c.dirty[k] = p
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+		if flushed {
+			c.onError(k, ErrQueueFull)
+			return
+		}
+		c.Flush(context.Background())
+	}
+}
+
+func (c *writeBehindCache[Key, Val]) Flush(ctx context.Context) error {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	c.mu.Lock()
+	batch := maps.Clone(c.dirty)
+	c.mu.Unlock()
+
+	failed := make(map[Key]struct{})
+	var first error
+	fail := func(k Key, err error) {
+		failed[k] = struct{}{}
+		c.onError(k, err)
+		if first == nil {
+			first = err
+		}
+	}
+
+	saves := make(map[Key]Val, c.batchSize)
+	flushSaves := func() {
+		if len(saves) == 0 {
+			return
+		}
+		if bs, ok := c.store.(BatchStore[Key, Val]); ok {
+			if err := bs.SaveMany(ctx, saves); err != nil {
+				for k := range saves {
+					fail(k, err)
+				}
+			}
+		} else {
+			for k, v := range saves {
+				if err := c.store.Save(ctx, k, v); err != nil {
+					fail(k, err)
+				}
+			}
+		}
+		clear(saves)
+	}
+	for k, p := range batch {
+		if p.deleted {
+			if err := c.store.Delete(ctx, k); err != nil {
+				fail(k, err)
+			}
+			continue
+		}
+		saves[k] = p.val
+		if len(saves) == c.batchSize {
+			flushSaves()
+		}
+	}
+	flushSaves()
+
+	c.mu.Lock()
+	for k, p := range batch {
+		if _, ok := failed[k]; !ok && c.dirty[k].seq == p.seq {
+			delete(c.dirty, k)
+		}
+	}
+	c.mu.Unlock()
+	return first
+}
+
+func (c *writeBehindCache[Key, Val]) write(k Key, p pendingWrite[Val]) {
+	var err error
+	if p.deleted {
+		err = c.store.Delete(context.Background(), k)
+	} else {
+		err = c.store.Save(context.Background(), k, p.val)
+	}
+	if err != nil {
+		c.onError(k, err)
+	}
+}
+
+func (c *writeBehindCache[Key, Val]) Close() error {
+	c.stop()
+	<-c.done
+
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.Flush(context.Background())
+}
+
+func (c *writeBehindCache[Key, Val]) run(ctx context.Context, interval time.Duration) {
+	defer close(c.done)
+	ticker := c.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			c.Flush(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type blockingStore struct {
+	mu      sync.Mutex
+	data    map[string]int
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingStore) Load(ctx context.Context, k string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[k]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return v, nil
+}
+
+func (s *blockingStore) Save(ctx context.Context, k string, v int) error {
+	if s.saving != nil {
+		s.saving <- struct{}{}
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[k] = v
+	return nil
+}
+
+func (s *blockingStore) Delete(ctx context.Context, k string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, k)
+	return nil
+}
+
+func TestWriteBehindKeepsDirtyUntilSaved(t *testing.T) {
+	store := &blockingStore{
+		data:    map[string]int{"a": 1},
+		saving:  make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	inner, err := NewLRU[string, int](10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewWriteBehind[string, int](inner, store, WithFlushInterval[string, int](time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Put("a", 2)
+	flushed := make(chan error)
+	go func() { flushed <- c.Flush(context.Background()) }()
+	<-store.saving
+
+	inner.Delete("a")
+	if v, err := c.GetContext(context.Background(), "a"); err != nil || v != 2 {
+		t.Fatalf("GetContext during flush = %v, %v; want 2, nil", v, err)
+	}
+	close(store.release)
+	if err := <-flushed; err != nil {
+		t.Fatal(err)
+	}
+	if n := c.Pending(); n != 0 {
+		t.Fatalf("Pending = %d after a successful flush", n)
+	}
+
+	store.saving = nil
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	c.Put("b", 3)
+	if v, err := store.Load(context.Background(), "b"); err != nil || v != 3 {
+		t.Fatalf("Put after Close reached the store as %v, %v; want 3, nil", v, err)
+	}
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type writeThroughCache[Key comparable, Val any] struct {
+	Cache[Key, Val]
+	store   Store[Key, Val]
+	flights flightGroup[Key, Val]
+	locks   stripedLocks[Key]
+}
+
+func NewWriteThrough[Key comparable, Val any](c Cache[Key, Val], store Store[Key, Val]) (*writeThroughCache[Key, Val], error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", ErrInvalidOption)
+	}
+	if store == nil {
+		return nil, fmt.Errorf("%w: store must not be nil", ErrInvalidOption)
+	}
+	return &writeThroughCache[Key, Val]{Cache: c, store: store}, nil
+}
+
+func (c *writeThroughCache[Key, Val]) Get(k Key) (Val, bool) {
+	v, err := c.GetContext(context.Background(), k)
+	return v, err == nil
+}
+
+func (c *writeThroughCache[Key, Val]) GetContext(ctx context.Context, k Key) (Val, error) {
+	if v, ok := c.Cache.Get(k); ok {
+		return v, nil
+	}
+	return c.flights.do(ctx, k, func(ctx context.Context) (Val, error) {
+		unlock := c.locks.lock(k)
+		defer unlock()
+
+		v, err := c.store.Load(ctx, k)
+		if err != nil {
+			return v, err
+		}
+		c.Cache.Put(k, v)
+		return v, nil
+	})
+}
+
+func (c *writeThroughCache[Key, Val]) Put(k Key, v Val) {
+	c.PutContext(context.Background(), k, v)
+}
+
+func (c *writeThroughCache[Key, Val]) PutContext(ctx context.Context, k Key, v Val) error {
+	unlock := c.locks.lock(k)
+	defer unlock()
+
+	if err := c.store.Save(ctx, k, v); err != nil {
+		c.Cache.Delete(k)
+		return err
+	}
+	c.Cache.Put(k, v)
+	return nil
+}
+
+func (c *writeThroughCache[Key, Val]) Delete(k Key) bool {
+	existed, err := c.delete(context.Background(), k)
+	return existed && err == nil
+}
+
+func (c *writeThroughCache[Key, Val]) DeleteContext(ctx context.Context, k Key) error {
+	_, err := c.delete(ctx, k)
+	return err
+}
+
+func (c *writeThroughCache[Key, Val]) delete(ctx context.Context, k Key) (bool, error) {
+	unlock := c.locks.lock(k)
+	defer unlock()
+
+	_, err := c.store.Load(ctx, k)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+	stored := err == nil
+	if err := c.store.Delete(ctx, k); err != nil {
+		return false, err
+	}
+	return c.Cache.Delete(k) || stored, nil
+}
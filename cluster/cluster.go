@@ -0,0 +1,115 @@
+package cluster
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+	"github.com/hashicorp/memberlist"
+)
+
+type PeerSetter interface {
+	SetPeers(peers ...string)
+}
+
+type cluster struct {
+	list    *memberlist.Memberlist
+	self    string
+	groups  []PeerSetter
+	mu      sync.Mutex
+	members map[string]string
+}
+
+func New(conf *memberlist.Config, self string, groups ...PeerSetter) (*cluster, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("%w: memberlist config must not be nil", cache.ErrInvalidOption)
+	}
+	if self == "" {
+		return nil, fmt.Errorf("%w: self address must not be empty", cache.ErrInvalidOption)
+	}
+	if len(self) > memberlist.MetaMaxSize {
+		return nil, fmt.Errorf("%w: self address exceeds %d bytes", cache.ErrInvalidOption, memberlist.MetaMaxSize)
+	}
+	c := &cluster{
+		self:    self,
+		groups:  groups,
+		members: make(map[string]string),
+	}
+	conf.Delegate = metaDelegate(self)
+	conf.Events = c
+	list, err := memberlist.Create(conf)
+	if err != nil {
+		return nil, err
+	}
+	c.list = list
+	return c, nil
+}
+
+func (c *cluster) Join(existing ...string) (int, error) {
+	return c.list.Join(existing)
+}
+
+func (c *cluster) Members() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	peers := slices.Collect(maps.Values(c.members))
+	slices.Sort(peers)
+	return peers
+}
+
+func (c *cluster) Leave(timeout time.Duration) error {
+	if err := c.list.Leave(timeout); err != nil {
+		return err
+	}
+	return c.list.Shutdown()
+}
+
+func (c *cluster) Close() error {
+	return c.list.Shutdown()
+}
+
+func (c *cluster) NotifyJoin(n *memberlist.Node) {
+	c.update(n.Name, string(n.Meta))
+}
+
+func (c *cluster) NotifyUpdate(n *memberlist.Node) {
+	c.update(n.Name, string(n.Meta))
+}
+
+func (c *cluster) NotifyLeave(n *memberlist.Node) {
+	c.update(n.Name, "")
+}
+
+func (c *cluster) update(name, addr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if addr == "" {
+		delete(c.members, name)
+	} else {
+		c.members[name] = addr
+	}
+	peers := slices.Collect(maps.Values(c.members))
+	slices.Sort(peers)
+	for _, g := range c.groups {
+		g.SetPeers(peers...)
+	}
+}
+
+type metaDelegate string
+
+func (d metaDelegate) NodeMeta(limit int) []byte {
+	return []byte(d)[:min(len(d), limit)]
+}
+
+func (metaDelegate) NotifyMsg([]byte) {}
+
+func (metaDelegate) GetBroadcasts(overhead, limit int) [][]byte { return nil }
+
+func (metaDelegate) LocalState(join bool) []byte { return nil }
+
+func (metaDelegate) MergeRemoteState(buf []byte, join bool) {}
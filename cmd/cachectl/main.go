@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const usage = `usage: cachectl [-addr URL] <command> [args]
+
+commands:
+  get <key>                 print the value stored under key
+  set [-ttl d] <key> [val]  store val (or stdin when omitted) under key
+  delete <key>              remove key
+  keys [prefix]             list keys, optionally filtered by prefix
+  stats                     print cache statistics
+  dump [limit]              print a table of cached entries
+`
+
+func main() {
+	addr := flag.String("addr", "http://localhost:8080", "cache server address")
+	timeout := flag.Duration("timeout", 5*time.Second, "request timeout")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c := &client{base: strings.TrimSuffix(*addr, "/"), http: &http.Client{Timeout: *timeout}}
+	if err := run(c, flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "cachectl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(c *client, cmd string, args []string) error {
+	switch cmd {
+	case "get":
+		if len(args) != 1 {
+			return errors.New("get takes exactly one key")
+		}
+		return c.do(http.MethodGet, "/keys/"+escapeKey(args[0]), nil)
+	case "set":
+		fs := flag.NewFlagSet("set", flag.ContinueOnError)
+		ttl := fs.Duration("ttl", 0, "entry time to live")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		var body io.Reader = os.Stdin
+		switch fs.NArg() {
+		case 1:
+		case 2:
+			body = strings.NewReader(fs.Arg(1))
+		default:
+			return errors.New("set takes a key and an optional value")
+		}
+		path := "/keys/" + escapeKey(fs.Arg(0))
+		if *ttl > 0 {
+			path += "?ttl=" + url.QueryEscape(ttl.String())
+		}
+		return c.do(http.MethodPut, path, body)
+	case "delete":
+		if len(args) != 1 {
+			return errors.New("delete takes exactly one key")
+		}
+		return c.do(http.MethodDelete, "/keys/"+escapeKey(args[0]), nil)
+	case "keys":
+		path := "/keys"
+		if len(args) > 0 {
+			path += "?prefix=" + url.QueryEscape(args[0])
+		}
+		return c.do(http.MethodGet, path, nil)
+	case "stats":
+		return c.do(http.MethodGet, "/stats", nil)
+	case "dump":
+		path := "/dump"
+		if len(args) > 0 {
+			path += "?limit=" + url.QueryEscape(args[0])
+		}
+		return c.do(http.MethodGet, path, nil)
+	}
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+type client struct {
+	base string
+	http *http.Client
+}
+
+func (c *client) do(method, path string, body io.Reader) error {
+	req, err := http.NewRequest(method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(out))
+	}
+	os.Stdout.Write(out)
+	return nil
+}
+
+func escapeKey(k string) string {
+	parts := strings.Split(k, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
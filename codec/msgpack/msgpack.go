@@ -0,0 +1,24 @@
+package msgpack
+
+import (
+	"io"
+
+	"github.com/assaidy/caches/cache"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var Codec cache.Codec = codec{}
+
+type codec struct{}
+
+func (codec) NewEncoder(w io.Writer) cache.Encoder {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc
+}
+
+func (codec) NewDecoder(r io.Reader) cache.Decoder {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	return dec
+}
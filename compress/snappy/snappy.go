@@ -0,0 +1,18 @@
+package snappy
+
+import (
+	"github.com/assaidy/caches/cache"
+	"github.com/golang/snappy"
+)
+
+var Compressor cache.Compressor = compressor{}
+
+type compressor struct{}
+
+func (compressor) Compress(src []byte) ([]byte, error) {
+	return snappy.Encode(nil, src), nil
+}
+
+func (compressor) Decompress(src []byte) ([]byte, error) {
+	return snappy.Decode(nil, src)
+}
@@ -0,0 +1,31 @@
+package zstd
+
+import (
+	"github.com/assaidy/caches/cache"
+	"github.com/klauspost/compress/zstd"
+)
+
+type compressor struct {
+	enc *zstd.Encoder
+	dec *zstd.Decoder
+}
+
+func New(level zstd.EncoderLevel) (cache.Compressor, error) {
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, err
+	}
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	return compressor{enc, dec}, nil
+}
+
+func (c compressor) Compress(src []byte) ([]byte, error) {
+	return c.enc.EncodeAll(src, nil), nil
+}
+
+func (c compressor) Decompress(src []byte) ([]byte, error) {
+	return c.dec.DecodeAll(src, nil)
+}
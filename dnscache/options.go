@@ -0,0 +1,71 @@
+package dnscache
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option func(*options)
+
+type options struct {
+	servers  []string
+	minTTL   time.Duration
+	maxTTL   time.Duration
+	timeout  time.Duration
+	fallback *net.Resolver
+}
+
+func WithServers(addrs ...string) Option {
+	return func(o *options) {
+		o.servers = addrs
+	}
+}
+
+func WithMinTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.minTTL = d
+	}
+}
+
+func WithMaxTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.maxTTL = d
+	}
+}
+
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+func WithFallback(r *net.Resolver) Option {
+	return func(o *options) {
+		o.fallback = r
+	}
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{
+		minTTL:   5 * time.Second,
+		maxTTL:   10 * time.Minute,
+		timeout:  2 * time.Second,
+		fallback: net.DefaultResolver,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.minTTL <= 0 || o.maxTTL <= 0 {
+		return o, cache.ErrInvalidTTL
+	}
+	if o.minTTL > o.maxTTL {
+		return o, fmt.Errorf("%w: min ttl must not exceed max ttl", cache.ErrInvalidOption)
+	}
+	if o.timeout <= 0 {
+		return o, fmt.Errorf("%w: timeout must be greater than zero", cache.ErrInvalidOption)
+	}
+	return o, nil
+}
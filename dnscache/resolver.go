@@ -0,0 +1,211 @@
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/miekg/dns"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/assaidy/caches/cache"
+)
+
+const lookupTimeout = 30 * time.Second
+
+var errNoAnswer = errors.New("no answer")
+
+type ttlStore[Val any] interface {
+	Get(k string) (Val, bool)
+	PutWithTTL(k string, v Val, d time.Duration)
+	Clear()
+}
+
+type srvRecord struct {
+	cname string
+	addrs []*net.SRV
+}
+
+type resolver struct {
+	client   *dns.Client
+	conf     *dns.ClientConfig
+	minTTL   time.Duration
+	maxTTL   time.Duration
+	fallback *net.Resolver
+	hosts    ttlStore[[]string]
+	srvs     ttlStore[srvRecord]
+	flights  singleflight.Group
+}
+
+func New(opts ...Option) (*resolver, error) {
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	conf := &dns.ClientConfig{Ndots: 1, Port: "53"}
+	if len(o.servers) == 0 {
+		if conf, err = dns.ClientConfigFromFile("/etc/resolv.conf"); err != nil {
+			return nil, err
+		}
+	} else {
+		conf.Servers = o.servers
+	}
+	hosts, err := cache.NewTTL[string, []string](o.maxTTL, false)
+	if err != nil {
+		return nil, err
+	}
+	srvs, err := cache.NewTTL[string, srvRecord](o.maxTTL, false)
+	if err != nil {
+		return nil, err
+	}
+	return &resolver{
+		client:   &dns.Client{Timeout: o.timeout},
+		conf:     conf,
+		minTTL:   o.minTTL,
+		maxTTL:   o.maxTTL,
+		fallback: o.fallback,
+		hosts:    hosts,
+		srvs:     srvs,
+	}, nil
+}
+
+func (r *resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{host}, nil
+	}
+	if addrs, ok := r.hosts.Get(host); ok {
+		return slices.Clone(addrs), nil
+	}
+	ch := r.flights.DoChan("host\x00"+host, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lookupTimeout)
+		defer cancel()
+
+		var addrs []string
+		ttl, err := r.query(ctx, host, func(rr dns.RR) {
+			switch rr := rr.(type) {
+			case *dns.A:
+				addrs = append(addrs, rr.A.String())
+			case *dns.AAAA:
+				addrs = append(addrs, rr.AAAA.String())
+			}
+		}, dns.TypeA, dns.TypeAAAA)
+		if err != nil {
+			if addrs, err = r.fallback.LookupHost(ctx, host); err != nil {
+				return nil, err
+			}
+			ttl = r.minTTL
+		}
+		r.hosts.PutWithTTL(host, addrs, ttl)
+		return addrs, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return slices.Clone(res.Val.([]string)), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *resolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	target := name
+	if service != "" || proto != "" {
+		target = "_" + service + "._" + proto + "." + name
+	}
+	if rec, ok := r.srvs.Get(target); ok {
+		return rec.cname, slices.Clone(rec.addrs), nil
+	}
+	ch := r.flights.DoChan("srv\x00"+target, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lookupTimeout)
+		defer cancel()
+
+		var rec srvRecord
+		ttl, err := r.query(ctx, target, func(rr dns.RR) {
+			if srv, ok := rr.(*dns.SRV); ok {
+				rec.cname = srv.Hdr.Name
+				rec.addrs = append(rec.addrs, &net.SRV{
+					Target:   srv.Target,
+					Port:     srv.Port,
+					Priority: srv.Priority,
+					Weight:   srv.Weight,
+				})
+			}
+		}, dns.TypeSRV)
+		if err != nil {
+			if rec.cname, rec.addrs, err = r.fallback.LookupSRV(ctx, service, proto, name); err != nil {
+				return srvRecord{}, err
+			}
+			ttl = r.minTTL
+		}
+		slices.SortStableFunc(rec.addrs, func(a, b *net.SRV) int {
+			return int(a.Priority) - int(b.Priority)
+		})
+		r.srvs.PutWithTTL(target, rec, ttl)
+		return rec, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return "", nil, res.Err
+		}
+		rec := res.Val.(srvRecord)
+		return rec.cname, slices.Clone(rec.addrs), nil
+	case <-ctx.Done():
+		return "", nil, ctx.Err()
+	}
+}
+
+func (r *resolver) Flush() {
+	r.hosts.Clear()
+	r.srvs.Clear()
+}
+
+func (r *resolver) query(ctx context.Context, name string, collect func(dns.RR), types ...uint16) (time.Duration, error) {
+	for _, fqdn := range r.conf.NameList(name) {
+		minTTL := uint32(math.MaxUint32)
+		found := false
+		for _, t := range types {
+			in, err := r.exchange(ctx, fqdn, t)
+			if err != nil {
+				return 0, err
+			}
+			for _, rr := range in.Answer {
+				if rr.Header().Rrtype != t {
+					continue
+				}
+				found = true
+				minTTL = min(minTTL, rr.Header().Ttl)
+				collect(rr)
+			}
+		}
+		if found {
+			return min(max(time.Duration(minTTL)*time.Second, r.minTTL), r.maxTTL), nil
+		}
+	}
+	return 0, errNoAnswer
+}
+
+func (r *resolver) exchange(ctx context.Context, fqdn string, t uint16) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(fqdn, t)
+	m.RecursionDesired = true
+	lastErr := errNoAnswer
+	for _, s := range r.conf.Servers {
+		addr := s
+		if _, _, err := net.SplitHostPort(s); err != nil {
+			addr = net.JoinHostPort(s, r.conf.Port)
+		}
+		in, _, err := r.client.ExchangeContext(ctx, m, addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return in, nil
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,87 @@
+package grpccache
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type ttlPutter interface {
+	PutWithTTL(k string, v []byte, d time.Duration)
+}
+
+func UnaryClientInterceptor(c cache.Cache[string, []byte], opts ...Option) (grpc.UnaryClientInterceptor, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	tp, hasTTL := c.(ttlPutter)
+	for _, ttl := range o.methods {
+		if ttl > 0 && !hasTTL {
+			return nil, fmt.Errorf("%w: cache does not support per-entry ttl", cache.ErrInvalidOption)
+		}
+	}
+	marshal := proto.MarshalOptions{Deterministic: true}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		ttl, ok := o.methods[method]
+		reqMsg, isReq := req.(proto.Message)
+		replyMsg, isReply := reply.(proto.Message)
+		if !ok || !isReq || !isReply {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if len(md.Get("authorization")) > 0 && !slices.Contains(o.metadata, "authorization") {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		b, err := marshal.Marshal(reqMsg)
+		if err != nil {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		key := requestKey(cc, method, md, o.metadata, b)
+		if data, ok := c.Get(key); ok {
+			if err := proto.Unmarshal(data, replyMsg); err == nil {
+				return nil
+			}
+			c.Delete(key)
+		}
+
+		if err := invoker(ctx, method, req, reply, cc, callOpts...); err != nil {
+			return err
+		}
+		data, err := proto.Marshal(replyMsg)
+		if err != nil {
+			return nil
+		}
+		if ttl > 0 {
+			tp.PutWithTTL(key, data, ttl)
+		} else {
+			c.Put(key, data)
+		}
+		return nil
+	}, nil
+}
+
+func requestKey(cc *grpc.ClientConn, method string, md metadata.MD, keys []string, req []byte) string {
+	var sb strings.Builder
+	if cc != nil {
+		sb.WriteString(cc.Target())
+	}
+	sb.WriteString("\x00" + method + "\x00")
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "%s=%q\x00", k, md.Get(k))
+	}
+	sb.Write(req)
+	return sb.String()
+}
@@ -0,0 +1,53 @@
+package grpccache
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option func(*options)
+
+type options struct {
+	methods  map[string]time.Duration
+	metadata []string
+}
+
+func WithMethod(method string, ttl time.Duration) Option {
+	return func(o *options) {
+		o.methods[method] = ttl
+	}
+}
+
+func WithMetadataKeys(keys ...string) Option {
+	return func(o *options) {
+		o.metadata = append(o.metadata, keys...)
+	}
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{methods: make(map[string]time.Duration)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(o.methods) == 0 {
+		return o, fmt.Errorf("%w: at least one method must be cacheable", cache.ErrInvalidOption)
+	}
+	for _, ttl := range o.methods {
+		if ttl < 0 {
+			return o, cache.ErrInvalidTTL
+		}
+	}
+	for i, k := range o.metadata {
+		if k == "" {
+			return o, fmt.Errorf("%w: metadata key must not be empty", cache.ErrInvalidOption)
+		}
+		o.metadata[i] = strings.ToLower(k)
+	}
+	slices.Sort(o.metadata)
+	o.metadata = slices.Compact(o.metadata)
+	return o, nil
+}
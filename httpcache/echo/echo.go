@@ -0,0 +1,16 @@
+package echo
+
+import (
+	goecho "github.com/labstack/echo/v4"
+
+	"github.com/assaidy/caches/cache"
+	"github.com/assaidy/caches/httpcache"
+)
+
+func Middleware(c cache.Cache[string, httpcache.Response], opts ...httpcache.Option) (goecho.MiddlewareFunc, error) {
+	mw, err := httpcache.Middleware(c, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return goecho.WrapMiddleware(mw), nil
+}
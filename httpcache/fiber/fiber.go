@@ -0,0 +1,73 @@
+package fiber
+
+import (
+	"net/http"
+
+	gofiber "github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"github.com/assaidy/caches/cache"
+	"github.com/assaidy/caches/httpcache"
+)
+
+func Middleware(c cache.Cache[string, httpcache.Response], opts ...httpcache.Option) (gofiber.Handler, error) {
+	mw, err := httpcache.Middleware(c, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return func(fc *gofiber.Ctx) error {
+		req, err := adaptor.ConvertRequest(fc, false)
+		if err != nil {
+			return err
+		}
+		var nextErr error
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if nextErr = fc.Next(); nextErr != nil {
+				return
+			}
+			resp := fc.Response()
+			h := w.Header()
+			resp.Header.VisitAll(func(k, v []byte) {
+				name := http.CanonicalHeaderKey(string(k))
+				if name != "Content-Length" && name != "Date" {
+					h.Add(name, string(v))
+				}
+			})
+			body := append([]byte(nil), resp.Body()...)
+			resp.ResetBody()
+			w.WriteHeader(resp.StatusCode())
+			w.Write(body)
+		})).ServeHTTP(&writer{ctx: fc, header: make(http.Header)}, req)
+		return nextErr
+	}, nil
+}
+
+type writer struct {
+	ctx         *gofiber.Ctx
+	header      http.Header
+	wroteHeader bool
+}
+
+func (w *writer) Header() http.Header {
+	return w.header
+}
+
+func (w *writer) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	resp := w.ctx.Response()
+	for k, vals := range w.header {
+		resp.Header.Del(k)
+		for _, v := range vals {
+			resp.Header.Add(k, v)
+		}
+	}
+	resp.SetStatusCode(status)
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.ctx.Write(b)
+}
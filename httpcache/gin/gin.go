@@ -0,0 +1,52 @@
+package gin
+
+import (
+	"net/http"
+
+	gogin "github.com/gin-gonic/gin"
+
+	"github.com/assaidy/caches/cache"
+	"github.com/assaidy/caches/httpcache"
+)
+
+func Middleware(c cache.Cache[string, httpcache.Response], opts ...httpcache.Option) (gogin.HandlerFunc, error) {
+	mw, err := httpcache.Middleware(c, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return func(gc *gogin.Context) {
+		orig, origReq := gc.Writer, gc.Request
+		called := false
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			gc.Writer = &writer{ResponseWriter: orig, w: w}
+			gc.Request = r
+			gc.Next()
+		})).ServeHTTP(orig, origReq)
+		gc.Writer, gc.Request = orig, origReq
+		if !called {
+			gc.Abort()
+		}
+	}, nil
+}
+
+type writer struct {
+	gogin.ResponseWriter
+	w http.ResponseWriter
+}
+
+func (w *writer) Header() http.Header {
+	return w.w.Header()
+}
+
+func (w *writer) WriteHeader(status int) {
+	w.w.WriteHeader(status)
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	return w.w.Write(b)
+}
+
+func (w *writer) WriteString(s string) (int, error) {
+	return w.w.Write([]byte(s))
+}
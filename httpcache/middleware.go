@@ -0,0 +1,185 @@
+package httpcache
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Response struct {
+	Status        int
+	Header        http.Header
+	Body          []byte
+	RequestHeader http.Header
+	StoredAt      time.Time
+}
+
+func Weigh(k string, r Response) int64 {
+	n := int64(len(k) + len(r.Body))
+	for name, vals := range r.Header {
+		for _, v := range vals {
+			n += int64(len(name) + len(v))
+		}
+	}
+	return n
+}
+
+type ttlPutter interface {
+	PutWithTTL(k string, v Response, d time.Duration)
+}
+
+type middleware struct {
+	cache       cache.Cache[string, Response]
+	ttl         time.Duration
+	varyHeaders []string
+	methods     []string
+	maxBodySize int
+}
+
+func Middleware(c cache.Cache[string, Response], opts ...Option) (func(http.Handler) http.Handler, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := c.(ttlPutter); o.ttl > 0 && !ok {
+		return nil, fmt.Errorf("%w: cache does not support per-entry ttl", cache.ErrInvalidOption)
+	}
+	m := &middleware{
+		cache:       c,
+		ttl:         o.ttl,
+		varyHeaders: o.varyHeaders,
+		methods:     o.methods,
+		maxBodySize: o.maxBodySize,
+	}
+	return m.wrap, nil
+}
+
+func (m *middleware) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(m.methods, r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := m.key(r)
+		if resp, ok := m.cache.Get(key); ok {
+			writeResponse(w, resp, "HIT")
+			return
+		}
+
+		w.Header().Set("X-Cache", "MISS")
+		outer := w.Header().Clone()
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK, limit: m.maxBodySize}
+		next.ServeHTTP(rec, r)
+		if rec.status != http.StatusOK || rec.overflow || !m.storable(r, rec.Header()) {
+			return
+		}
+		resp := Response{Status: rec.status, Header: handlerHeader(outer, rec.Header()), Body: rec.body}
+		if m.ttl > 0 {
+			m.cache.(ttlPutter).PutWithTTL(key, resp, m.ttl)
+		} else {
+			m.cache.Put(key, resp)
+		}
+	})
+}
+
+func (m *middleware) key(r *http.Request) string {
+	var b strings.Builder
+	b.WriteString(r.Method)
+	b.WriteByte(' ')
+	b.WriteString(r.URL.RequestURI())
+	for _, h := range m.varyHeaders {
+		b.WriteByte('\n')
+		b.WriteString(h)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(r.Header.Values(h), ","))
+	}
+	return b.String()
+}
+
+func writeResponse(w http.ResponseWriter, resp Response, status string) {
+	h := w.Header()
+	for k, v := range resp.Header {
+		h[k] = slices.Clone(v)
+	}
+	h.Set("X-Cache", status)
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+func handlerHeader(outer, h http.Header) http.Header {
+	own := make(http.Header, len(h))
+	for name, vals := range h {
+		if !slices.Equal(outer[name], vals) {
+			own[name] = slices.Clone(vals)
+		}
+	}
+	return own
+}
+
+func (m *middleware) storable(r *http.Request, h http.Header) bool {
+	if h.Get("Set-Cookie") != "" {
+		return false
+	}
+	if r.Header.Get("Cookie") != "" && !slices.Contains(m.varyHeaders, "Cookie") {
+		return false
+	}
+	for _, v := range h.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name != "" && !slices.Contains(m.varyHeaders, name) {
+				return false
+			}
+		}
+	}
+	public := false
+	for _, d := range strings.Split(h.Get("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(d)) {
+		case "no-store", "private", "no-cache":
+			return false
+		case "public":
+			public = true
+		}
+	}
+	return public || r.Header.Get("Authorization") == ""
+}
+
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	body        []byte
+	limit       int
+	overflow    bool
+	wroteHeader bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	if !r.overflow {
+		if len(r.body)+len(b) > r.limit {
+			r.overflow = true
+			r.body = nil
+		} else {
+			r.body = append(r.body, b...)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,65 @@
+package httpcache
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option func(*options)
+
+type options struct {
+	ttl         time.Duration
+	varyHeaders []string
+	methods     []string
+	maxBodySize int
+}
+
+func WithTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.ttl = d
+	}
+}
+
+func WithVaryHeaders(headers ...string) Option {
+	return func(o *options) {
+		o.varyHeaders = headers
+	}
+}
+
+func WithMethods(methods ...string) Option {
+	return func(o *options) {
+		o.methods = methods
+	}
+}
+
+func WithMaxBodySize(n int) Option {
+	return func(o *options) {
+		o.maxBodySize = n
+	}
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{
+		methods:     []string{http.MethodGet, http.MethodHead},
+		maxBodySize: 1 << 20,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ttl < 0 {
+		return o, cache.ErrInvalidTTL
+	}
+	if len(o.methods) == 0 {
+		return o, fmt.Errorf("%w: at least one method must be cacheable", cache.ErrInvalidOption)
+	}
+	if o.maxBodySize <= 0 {
+		return o, fmt.Errorf("%w: max body size must be greater than zero", cache.ErrInvalidOption)
+	}
+	for i, h := range o.varyHeaders {
+		o.varyHeaders[i] = http.CanonicalHeaderKey(h)
+	}
+	return o, nil
+}
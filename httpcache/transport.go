@@ -0,0 +1,249 @@
+package httpcache
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+var cacheableStatus = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusGone,
+	http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+type transport struct {
+	cache       cache.Cache[string, Response]
+	next        http.RoundTripper
+	maxBodySize int
+	clock       func() time.Time
+}
+
+func NewTransport(c cache.Cache[string, Response], next http.RoundTripper, opts ...Option) (*transport, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{cache: c, next: next, maxBodySize: o.maxBodySize, clock: time.Now}, nil
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.String()
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < 400 {
+			t.cache.Delete(http.MethodGet + " " + req.URL.String())
+			t.cache.Delete(http.MethodHead + " " + req.URL.String())
+		}
+		return resp, err
+	}
+	reqCC := parseCacheControl(req.Header)
+	if _, ok := reqCC["no-store"]; ok {
+		return t.next.RoundTrip(req)
+	}
+
+	cached, ok := t.cache.Get(key)
+	if ok && !varyMatches(cached, req) {
+		ok = false
+	}
+	if ok && t.fresh(cached, reqCC) {
+		return toHTTPResponse(cached, req, "HIT"), nil
+	}
+
+	outReq := req
+	if ok {
+		if etag := cached.Header.Get("ETag"); etag != "" || cached.Header.Get("Last-Modified") != "" {
+			outReq = req.Clone(req.Context())
+			if etag != "" {
+				outReq.Header.Set("If-None-Match", etag)
+			}
+			if lm := cached.Header.Get("Last-Modified"); lm != "" {
+				outReq.Header.Set("If-Modified-Since", lm)
+			}
+		}
+	}
+
+	resp, err := t.next.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	if ok && outReq != req && resp.StatusCode == http.StatusNotModified {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		cached.Header = cached.Header.Clone()
+		for name, vals := range resp.Header {
+			cached.Header[name] = vals
+		}
+		cached.StoredAt = t.clock()
+		t.cache.Put(key, cached)
+		return toHTTPResponse(cached, req, "REVALIDATED"), nil
+	}
+	if !t.storable(req, reqCC, resp) {
+		resp.Header.Set("X-Cache", "MISS")
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(t.maxBodySize)+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > t.maxBodySize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		resp.Header.Set("X-Cache", "MISS")
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	entry := Response{
+		Status:        resp.StatusCode,
+		Header:        resp.Header.Clone(),
+		Body:          body,
+		RequestHeader: varyHeaders(resp.Header, req),
+		StoredAt:      t.clock(),
+	}
+	t.cache.Put(key, entry)
+	return toHTTPResponse(entry, req, "MISS"), nil
+}
+
+func (t *transport) storable(req *http.Request, reqCC map[string]string, resp *http.Response) bool {
+	if !slices.Contains(cacheableStatus, resp.StatusCode) {
+		return false
+	}
+	cc := parseCacheControl(resp.Header)
+	if _, ok := cc["no-store"]; ok {
+		return false
+	}
+	if strings.TrimSpace(resp.Header.Get("Vary")) == "*" {
+		return false
+	}
+	if req.Header.Get("Authorization") != "" {
+		_, public := cc["public"]
+		if !public {
+			return false
+		}
+	}
+	_, maxAge := cc["max-age"]
+	return maxAge || resp.Header.Get("Expires") != "" || resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+func (t *transport) fresh(r Response, reqCC map[string]string) bool {
+	cc := parseCacheControl(r.Header)
+	if _, ok := cc["no-cache"]; ok {
+		return false
+	}
+	if _, ok := reqCC["no-cache"]; ok {
+		return false
+	}
+	lifetime := freshness(r.Header, cc)
+	if v, ok := reqCC["max-age"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			lifetime = min(lifetime, time.Duration(n)*time.Second)
+		}
+	}
+	age := t.clock().Sub(r.StoredAt)
+	if n, err := strconv.Atoi(r.Header.Get("Age")); err == nil && n > 0 {
+		age += time.Duration(n) * time.Second
+	}
+	return age < lifetime
+}
+
+func freshness(h http.Header, cc map[string]string) time.Duration {
+	if v, ok := cc["max-age"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			return time.Duration(n) * time.Second
+		}
+		return 0
+	}
+	date, err := http.ParseTime(h.Get("Date"))
+	if err != nil {
+		return 0
+	}
+	if exp := h.Get("Expires"); exp != "" {
+		t, err := http.ParseTime(exp)
+		if err != nil {
+			return 0
+		}
+		return t.Sub(date)
+	}
+	if lm, err := http.ParseTime(h.Get("Last-Modified")); err == nil && lm.Before(date) {
+		return date.Sub(lm) / 10
+	}
+	return 0
+}
+
+func parseCacheControl(h http.Header) map[string]string {
+	cc := make(map[string]string)
+	for _, v := range h.Values("Cache-Control") {
+		for _, d := range strings.Split(v, ",") {
+			name, val, _ := strings.Cut(strings.TrimSpace(d), "=")
+			if name != "" {
+				cc[strings.ToLower(name)] = strings.Trim(val, `"`)
+			}
+		}
+	}
+	return cc
+}
+
+func varyHeaders(resp http.Header, req *http.Request) http.Header {
+	var h http.Header
+	for _, v := range resp.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				if h == nil {
+					h = make(http.Header)
+				}
+				h[http.CanonicalHeaderKey(name)] = req.Header.Values(name)
+			}
+		}
+	}
+	return h
+}
+
+func varyMatches(r Response, req *http.Request) bool {
+	for name, vals := range r.RequestHeader {
+		if !slices.Equal(vals, req.Header.Values(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func toHTTPResponse(r Response, req *http.Request, status string) *http.Response {
+	h := r.Header.Clone()
+	h.Set("X-Cache", status)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,64 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/assaidy/caches/cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type statser interface {
+	Stats() cache.Stats
+}
+
+func RegisterMetrics(meter metric.Meter, c statser, attrs ...attribute.KeyValue) (metric.Registration, error) {
+	hits, err := meter.Int64ObservableCounter("cache.hits", metric.WithDescription("Number of cache hits."))
+	if err != nil {
+		return nil, err
+	}
+	misses, err := meter.Int64ObservableCounter("cache.misses", metric.WithDescription("Number of cache misses."))
+	if err != nil {
+		return nil, err
+	}
+	evictions, err := meter.Int64ObservableCounter("cache.evictions", metric.WithDescription("Number of entries evicted due to capacity."))
+	if err != nil {
+		return nil, err
+	}
+	expirations, err := meter.Int64ObservableCounter("cache.expirations", metric.WithDescription("Number of entries removed due to expiry."))
+	if err != nil {
+		return nil, err
+	}
+	size, err := meter.Int64ObservableGauge("cache.size", metric.WithDescription("Number of entries in the cache."))
+	if err != nil {
+		return nil, err
+	}
+
+	set := metric.WithAttributes(attrs...)
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		st := c.Stats()
+		o.ObserveInt64(hits, int64(st.Hits), set)
+		o.ObserveInt64(misses, int64(st.Misses), set)
+		o.ObserveInt64(evictions, int64(st.Evictions), set)
+		o.ObserveInt64(expirations, int64(st.Expirations), set)
+		o.ObserveInt64(size, int64(st.Size), set)
+		return nil
+	}, hits, misses, evictions, expirations, size)
+}
+
+func TraceLoader[Key comparable, Val any](tracer trace.Tracer, fn cache.LoaderFunc[Key, Val]) cache.LoaderFunc[Key, Val] {
+	return func(ctx context.Context, k Key) (Val, error) {
+		ctx, span := tracer.Start(ctx, "cache.load", trace.WithAttributes(attribute.String("cache.key", fmt.Sprint(k))))
+		defer span.End()
+
+		v, err := fn(ctx, k)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return v, err
+	}
+}
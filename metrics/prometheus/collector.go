@@ -0,0 +1,88 @@
+package prometheus
+
+import (
+	"github.com/assaidy/caches/cache"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+type statser interface {
+	Stats() cache.Stats
+}
+
+type collector struct {
+	cache       statser
+	hits        *prom.Desc
+	misses      *prom.Desc
+	hitRatio    *prom.Desc
+	size        *prom.Desc
+	evictions   *prom.Desc
+	expirations *prom.Desc
+	loads       *prom.Desc
+	failures    *prom.Desc
+	loadTime    *prom.Desc
+	evictionAge *prom.Desc
+	hitTTL      *prom.Desc
+}
+
+func Collector(c statser, labels prom.Labels) prom.Collector {
+	desc := func(name, help string) *prom.Desc {
+		return prom.NewDesc("cache_"+name, help, nil, labels)
+	}
+	return &collector{
+		cache:       c,
+		hits:        desc("hits_total", "Number of cache hits."),
+		misses:      desc("misses_total", "Number of cache misses."),
+		hitRatio:    desc("hit_ratio", "Ratio of hits to total lookups."),
+		size:        desc("size", "Number of entries in the cache."),
+		evictions:   desc("evictions_total", "Number of entries evicted due to capacity."),
+		expirations: desc("expirations_total", "Number of entries removed due to expiry."),
+		loads:       desc("loads_total", "Number of loader calls."),
+		failures:    desc("load_failures_total", "Number of loader calls that returned an error."),
+		loadTime:    desc("load_duration_seconds", "Loader call latency."),
+		evictionAge: desc("eviction_age_seconds", "Age of entries when they were evicted or expired."),
+		hitTTL:      desc("hit_ttl_seconds", "Remaining time to live of entries when they were hit."),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.hitRatio
+	ch <- c.size
+	ch <- c.evictions
+	ch <- c.expirations
+	ch <- c.loads
+	ch <- c.failures
+	ch <- c.loadTime
+	ch <- c.evictionAge
+	ch <- c.hitTTL
+}
+
+func (c *collector) Collect(ch chan<- prom.Metric) {
+	st := c.cache.Stats()
+	ch <- prom.MustNewConstMetric(c.hits, prom.CounterValue, float64(st.Hits))
+	ch <- prom.MustNewConstMetric(c.misses, prom.CounterValue, float64(st.Misses))
+	ch <- prom.MustNewConstMetric(c.hitRatio, prom.GaugeValue, st.HitRatio())
+	ch <- prom.MustNewConstMetric(c.size, prom.GaugeValue, float64(st.Size))
+	ch <- prom.MustNewConstMetric(c.evictions, prom.CounterValue, float64(st.Evictions))
+	ch <- prom.MustNewConstMetric(c.expirations, prom.CounterValue, float64(st.Expirations))
+	ch <- prom.MustNewConstMetric(c.loads, prom.CounterValue, float64(st.Loads))
+	ch <- prom.MustNewConstMetric(c.failures, prom.CounterValue, float64(st.LoadFailures))
+	ch <- histogram(c.loadTime, st.LoadTime)
+	if st.EvictionAge.Bounds != nil {
+		ch <- histogram(c.evictionAge, st.EvictionAge)
+	}
+	if st.HitTTL.Bounds != nil {
+		ch <- histogram(c.hitTTL, st.HitTTL)
+	}
+}
+
+func histogram(desc *prom.Desc, h cache.Histogram) prom.Metric {
+	buckets := make(map[float64]uint64, len(h.Bounds))
+	var cumulative uint64
+	for i, b := range h.Bounds {
+		cumulative += h.Counts[i]
+		buckets[b.Seconds()] = cumulative
+	}
+	return prom.MustNewConstHistogram(desc, h.Count, h.Sum.Seconds(), buckets)
+}
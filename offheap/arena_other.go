@@ -0,0 +1,11 @@
+//go:build !unix
+
+package offheap
+
+func mapArena(size int) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+func unmapArena(b []byte) error {
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package offheap
+
+import "syscall"
+
+func mapArena(size int) ([]byte, error) {
+	return syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+}
+
+func unmapArena(b []byte) error {
+	return syscall.Munmap(b)
+}
@@ -0,0 +1,156 @@
+package offheap
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type slot struct {
+	off int
+	n   int
+	gen uint64
+}
+
+type alloc[Key comparable] struct {
+	key Key
+	off int
+	n   int
+	gen uint64
+	pad bool
+}
+
+type blobCache[Key comparable] struct {
+	arena     []byte
+	index     map[Key]slot
+	allocs    []alloc[Key]
+	head      int
+	used      int
+	gen       uint64
+	closed    bool
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	mu        sync.Mutex
+}
+
+var _ cache.Cache[string, []byte] = (*blobCache[string])(nil)
+
+func New[Key comparable](size int) (*blobCache[Key], error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: arena size must be greater than zero", cache.ErrInvalidOption)
+	}
+	arena, err := mapArena(size)
+	if err != nil {
+		return nil, err
+	}
+	return &blobCache[Key]{
+		arena: arena,
+		index: make(map[Key]slot),
+	}, nil
+}
+
+func (c *blobCache[Key]) Get(k Key) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.index[k]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	v := make([]byte, s.n)
+	copy(v, c.arena[s.off:s.off+s.n])
+	return v, true
+}
+
+func (c *blobCache[Key]) Put(k Key, v []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || len(v) > len(c.arena) {
+		return
+	}
+	delete(c.index, k)
+	if c.head+len(v) > len(c.arena) {
+		c.reserve(len(c.arena) - c.head)
+		c.allocs = append(c.allocs, alloc[Key]{off: c.head, n: len(c.arena) - c.head, pad: true})
+		c.used += len(c.arena) - c.head
+		c.head = 0
+	}
+	c.reserve(len(v))
+
+	c.gen++
+	copy(c.arena[c.head:], v)
+	c.allocs = append(c.allocs, alloc[Key]{key: k, off: c.head, n: len(v), gen: c.gen})
+	c.index[k] = slot{c.head, len(v), c.gen}
+	c.used += len(v)
+	c.head += len(v)
+	if c.head == len(c.arena) {
+		c.head = 0
+	}
+}
+
+func (c *blobCache[Key]) Delete(k Key) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.index[k]; ok {
+		delete(c.index, k)
+		return true
+	}
+	return false
+}
+
+func (c *blobCache[Key]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.index)
+}
+
+func (c *blobCache[Key]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.index = make(map[Key]slot)
+	c.allocs = nil
+	c.head = 0
+	c.used = 0
+}
+
+func (c *blobCache[Key]) Stats() cache.Stats {
+	return cache.Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      c.Len(),
+	}
+}
+
+func (c *blobCache[Key]) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.index = make(map[Key]slot)
+	c.allocs = nil
+	return unmapArena(c.arena)
+}
+
+func (c *blobCache[Key]) reserve(n int) {
+	for c.used+n > len(c.arena) {
+		a := c.allocs[0]
+		c.allocs = c.allocs[1:]
+		c.used -= a.n
+		if s, ok := c.index[a.key]; !a.pad && ok && s.gen == a.gen {
+			delete(c.index, a.key)
+			c.evictions.Add(1)
+		}
+	}
+}
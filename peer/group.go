@@ -0,0 +1,174 @@
+package peer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/assaidy/caches/cache"
+	"golang.org/x/sync/singleflight"
+)
+
+type group[Val any] struct {
+	name     string
+	self     string
+	local    cache.Cache[string, Val]
+	loader   cache.LoaderFunc[string, Val]
+	replicas int
+	basePath string
+	client   *http.Client
+	vals     cache.Serializer[Val]
+	logger   *slog.Logger
+	flights  singleflight.Group
+	mu       sync.RWMutex
+	ring     *ring
+}
+
+func NewGroup[Val any](name, self string, local cache.Cache[string, Val], loader cache.LoaderFunc[string, Val], opts ...Option[Val]) (*group[Val], error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: group name must not be empty", cache.ErrInvalidOption)
+	}
+	if self == "" {
+		return nil, fmt.Errorf("%w: self address must not be empty", cache.ErrInvalidOption)
+	}
+	if local == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	if loader == nil {
+		return nil, fmt.Errorf("%w: loader must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &group[Val]{
+		name:     name,
+		self:     self,
+		local:    local,
+		loader:   loader,
+		replicas: o.replicas,
+		basePath: o.basePath,
+		client:   o.client,
+		vals:     o.vals,
+		logger:   o.logger,
+		ring:     newRing(o.replicas, []string{self}),
+	}, nil
+}
+
+func (g *group[Val]) SetPeers(peers ...string) {
+	r := newRing(g.replicas, peers)
+	g.mu.Lock()
+	g.ring = r
+	g.mu.Unlock()
+}
+
+func (g *group[Val]) Get(ctx context.Context, k string) (Val, error) {
+	if v, ok := g.local.Get(k); ok {
+		return v, nil
+	}
+	g.mu.RLock()
+	owner := g.ring.owner(k)
+	g.mu.RUnlock()
+	if owner == "" || owner == g.self {
+		return g.load(ctx, k)
+	}
+
+	v, err := g.fetch(ctx, owner, k)
+	if err == nil {
+		g.local.Put(k, v)
+		return v, nil
+	}
+	if errors.Is(err, cache.ErrNotFound) || ctx.Err() != nil {
+		return v, err
+	}
+	g.logger.Debug("peer fetch failed", "peer", owner, "key", k, "err", err)
+	return g.load(ctx, k)
+}
+
+func (g *group[Val]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest, ok := strings.CutPrefix(r.URL.EscapedPath(), g.basePath)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	name, key, ok := strings.Cut(rest, "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	name, err := url.PathUnescape(name)
+	if err != nil || name != g.name {
+		http.NotFound(w, r)
+		return
+	}
+	if key, err = url.PathUnescape(key); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	v, err := g.load(r.Context(), key)
+	if errors.Is(err, cache.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body, err := g.vals.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(body)
+}
+
+func (g *group[Val]) load(ctx context.Context, k string) (Val, error) {
+	res, err, _ := g.flights.Do(k, func() (any, error) {
+		if v, ok := g.local.Get(k); ok {
+			return v, nil
+		}
+		v, err := g.loader(ctx, k)
+		if err != nil {
+			return v, err
+		}
+		g.local.Put(k, v)
+		return v, nil
+	})
+	v, _ := res.(Val)
+	return v, err
+}
+
+func (g *group[Val]) fetch(ctx context.Context, peer, k string) (Val, error) {
+	var z Val
+	u := strings.TrimSuffix(peer, "/") + g.basePath + url.PathEscape(g.name) + "/" + url.PathEscape(k)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return z, err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return z, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return z, cache.ErrNotFound
+	default:
+		return z, fmt.Errorf("peer %s: %s", peer, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return z, err
+	}
+	return g.vals.Unmarshal(body)
+}
@@ -0,0 +1,77 @@
+package peer
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option[Val any] func(*options[Val])
+
+type options[Val any] struct {
+	replicas int
+	basePath string
+	client   *http.Client
+	vals     cache.Serializer[Val]
+	logger   *slog.Logger
+}
+
+func WithReplicas[Val any](n int) Option[Val] {
+	return func(o *options[Val]) {
+		o.replicas = n
+	}
+}
+
+func WithBasePath[Val any](path string) Option[Val] {
+	return func(o *options[Val]) {
+		o.basePath = path
+	}
+}
+
+func WithHTTPClient[Val any](client *http.Client) Option[Val] {
+	return func(o *options[Val]) {
+		o.client = client
+	}
+}
+
+func WithSerializer[Val any](ser cache.Serializer[Val]) Option[Val] {
+	return func(o *options[Val]) {
+		o.vals = ser
+	}
+}
+
+func WithLogger[Val any](logger *slog.Logger) Option[Val] {
+	return func(o *options[Val]) {
+		o.logger = logger
+	}
+}
+
+func applyOptions[Val any](opts []Option[Val]) (options[Val], error) {
+	o := options[Val]{
+		replicas: 50,
+		basePath: "/_caches/",
+		client:   http.DefaultClient,
+		logger:   slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.replicas <= 0 {
+		return o, fmt.Errorf("%w: replicas must be greater than zero", cache.ErrInvalidOption)
+	}
+	if o.basePath == "" || o.basePath[0] != '/' {
+		return o, fmt.Errorf("%w: base path must start with a slash", cache.ErrInvalidOption)
+	}
+	if o.client == nil {
+		return o, fmt.Errorf("%w: http client must not be nil", cache.ErrInvalidOption)
+	}
+	if o.logger == nil {
+		return o, fmt.Errorf("%w: logger must not be nil", cache.ErrInvalidOption)
+	}
+	if o.vals == nil {
+		o.vals, _ = cache.NewSerializer[Val](cache.GobCodec)
+	}
+	return o, nil
+}
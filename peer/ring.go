@@ -0,0 +1,45 @@
+package peer
+
+import (
+	"hash/crc32"
+	"slices"
+	"strconv"
+)
+
+type ring struct {
+	replicas int
+	hashes   []uint32
+	owners   map[uint32]string
+}
+
+func newRing(replicas int, peers []string) *ring {
+	r := &ring{
+		replicas: replicas,
+		hashes:   make([]uint32, 0, replicas*len(peers)),
+		owners:   make(map[uint32]string, replicas*len(peers)),
+	}
+	for _, p := range peers {
+		for i := range replicas {
+			h := crc32.ChecksumIEEE([]byte(strconv.Itoa(i) + p))
+			if _, ok := r.owners[h]; ok {
+				continue
+			}
+			r.hashes = append(r.hashes, h)
+			r.owners[h] = p
+		}
+	}
+	slices.Sort(r.hashes)
+	return r
+}
+
+func (r *ring) owner(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	h := crc32.ChecksumIEEE([]byte(key))
+	i, _ := slices.BinarySearch(r.hashes, h)
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.owners[r.hashes[i]]
+}
@@ -0,0 +1,265 @@
+package persistent
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	entriesBucket   = []byte("entries")
+	deadlinesBucket = []byte("deadlines")
+)
+
+type boltCache[Key comparable, Val any] struct {
+	db          *bolt.DB
+	ttl         time.Duration
+	keys        cache.Serializer[Key]
+	vals        cache.Serializer[Val]
+	logger      *slog.Logger
+	clock       cache.Clock
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	expirations atomic.Uint64
+}
+
+var _ cache.Cache[string, any] = (*boltCache[string, any])(nil)
+
+func New[Key comparable, Val any](path string, opts ...Option[Key, Val]) (*boltCache[Key, Val], error) {
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(entriesBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(deadlinesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltCache[Key, Val]{
+		db:     db,
+		ttl:    o.ttl,
+		keys:   o.keys,
+		vals:   o.vals,
+		logger: o.logger,
+		clock:  o.clock,
+	}, nil
+}
+
+func (c *boltCache[Key, Val]) Get(k Key) (Val, bool) {
+	v, err := c.get(k)
+	return v, err == nil
+}
+
+func (c *boltCache[Key, Val]) get(k Key) (Val, error) {
+	var z Val
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		return z, err
+	}
+
+	var raw, expired []byte
+	err = c.db.View(func(tx *bolt.Tx) error {
+		rec := tx.Bucket(entriesBucket).Get(key)
+		if rec == nil {
+			return cache.ErrNotFound
+		}
+		if deadline := int64(binary.BigEndian.Uint64(rec)); deadline != 0 && c.clock.Now().UnixNano() >= deadline {
+			expired = bytes.Clone(rec[:8])
+			return cache.ErrNotFound
+		}
+		raw = bytes.Clone(rec[8:])
+		return nil
+	})
+	if expired != nil {
+		c.expirations.Add(1)
+		c.expireKey(key, expired)
+	}
+	if err != nil {
+		c.misses.Add(1)
+		return z, err
+	}
+
+	v, err := c.vals.Unmarshal(raw)
+	if err != nil {
+		c.misses.Add(1)
+		return z, err
+	}
+	c.hits.Add(1)
+	return v, nil
+}
+
+func (c *boltCache[Key, Val]) Put(k Key, v Val) {
+	if err := c.put(k, v, c.ttl); err != nil {
+		c.logger.Debug("persistent cache put failed", "key", k, "err", err)
+	}
+}
+
+func (c *boltCache[Key, Val]) PutWithTTL(k Key, v Val, d time.Duration) {
+	if err := c.put(k, v, d); err != nil {
+		c.logger.Debug("persistent cache put failed", "key", k, "err", err)
+	}
+}
+
+func (c *boltCache[Key, Val]) put(k Key, v Val, ttl time.Duration) error {
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		return err
+	}
+	val, err := c.vals.Marshal(v)
+	if err != nil {
+		return err
+	}
+	rec := append(make([]byte, 8, 8+len(val)), val...)
+	var deadline int64
+	if ttl > 0 {
+		deadline = c.clock.Now().Add(ttl).UnixNano()
+	}
+	binary.BigEndian.PutUint64(rec, uint64(deadline))
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		entries, deadlines := tx.Bucket(entriesBucket), tx.Bucket(deadlinesBucket)
+		if old := entries.Get(key); old != nil {
+			if err := deadlines.Delete(deadlineKey(old[:8], key)); err != nil {
+				return err
+			}
+		}
+		if deadline != 0 {
+			if err := deadlines.Put(deadlineKey(rec[:8], key), nil); err != nil {
+				return err
+			}
+		}
+		return entries.Put(key, rec)
+	})
+}
+
+func (c *boltCache[Key, Val]) Delete(k Key) bool {
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		return false
+	}
+	return c.deleteKey(key)
+}
+
+func (c *boltCache[Key, Val]) expireKey(key, deadline []byte) {
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		old := entries.Get(key)
+		if old == nil || !bytes.Equal(old[:8], deadline) {
+			return nil
+		}
+		if err := tx.Bucket(deadlinesBucket).Delete(deadlineKey(old[:8], key)); err != nil {
+			return err
+		}
+		return entries.Delete(key)
+	})
+	if err != nil {
+		c.logger.Debug("persistent cache expire failed", "err", err)
+	}
+}
+
+func (c *boltCache[Key, Val]) deleteKey(key []byte) bool {
+	deleted := false
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		old := entries.Get(key)
+		if old == nil {
+			return nil
+		}
+		if err := tx.Bucket(deadlinesBucket).Delete(deadlineKey(old[:8], key)); err != nil {
+			return err
+		}
+		deleted = true
+		return entries.Delete(key)
+	})
+	if err != nil {
+		c.logger.Debug("persistent cache delete failed", "err", err)
+		return false
+	}
+	return deleted
+}
+
+func (c *boltCache[Key, Val]) Len() int {
+	n := 0
+	c.db.View(func(tx *bolt.Tx) error {
+		n = tx.Bucket(entriesBucket).Stats().KeyN
+		return nil
+	})
+	return n
+}
+
+func (c *boltCache[Key, Val]) Clear() {
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{entriesBucket, deadlinesBucket} {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.logger.Debug("persistent cache clear failed", "err", err)
+	}
+}
+
+func (c *boltCache[Key, Val]) Stats() cache.Stats {
+	return cache.Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Expirations: c.expirations.Load(),
+		Size:        c.Len(),
+	}
+}
+
+func (c *boltCache[Key, Val]) Cleanup() error {
+	now := make([]byte, 8)
+	binary.BigEndian.PutUint64(now, uint64(c.clock.Now().UnixNano()))
+
+	n := 0
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		entries, deadlines := tx.Bucket(entriesBucket), tx.Bucket(deadlinesBucket)
+		var due [][]byte
+		cur := deadlines.Cursor()
+		for dk, _ := cur.First(); dk != nil && bytes.Compare(dk[:8], now) <= 0; dk, _ = cur.Next() {
+			due = append(due, bytes.Clone(dk))
+		}
+		for _, dk := range due {
+			if err := entries.Delete(dk[8:]); err != nil {
+				return err
+			}
+			if err := deadlines.Delete(dk); err != nil {
+				return err
+			}
+		}
+		n = len(due)
+		return nil
+	})
+	c.expirations.Add(uint64(n))
+	c.logger.Debug("persistent cache cleanup", "expired", n, "err", err)
+	return err
+}
+
+func (c *boltCache[Key, Val]) Close() error {
+	return c.db.Close()
+}
+
+func deadlineKey(deadline, key []byte) []byte {
+	return append(bytes.Clone(deadline), key...)
+}
@@ -0,0 +1,178 @@
+package persistent
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS cache_entries (
+	key        BLOB PRIMARY KEY,
+	value      BLOB NOT NULL,
+	expires_at INTEGER
+);
+CREATE INDEX IF NOT EXISTS cache_entries_expires_at ON cache_entries (expires_at) WHERE expires_at IS NOT NULL;
+`
+
+type sqliteCache[Key comparable, Val any] struct {
+	db          *sql.DB
+	ttl         time.Duration
+	keys        cache.Serializer[Key]
+	vals        cache.Serializer[Val]
+	logger      *slog.Logger
+	clock       cache.Clock
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	expirations atomic.Uint64
+}
+
+var _ cache.Cache[string, any] = (*sqliteCache[string, any])(nil)
+
+func NewSQLite[Key comparable, Val any](path string, opts ...Option[Key, Val]) (*sqliteCache[Key, Val], error) {
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteCache[Key, Val]{
+		db:     db,
+		ttl:    o.ttl,
+		keys:   o.keys,
+		vals:   o.vals,
+		logger: o.logger,
+		clock:  o.clock,
+	}, nil
+}
+
+func (c *sqliteCache[Key, Val]) Get(k Key) (Val, bool) {
+	v, err := c.get(k)
+	return v, err == nil
+}
+
+func (c *sqliteCache[Key, Val]) get(k Key) (Val, error) {
+	var z Val
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		return z, err
+	}
+
+	var (
+		raw       []byte
+		expiresAt sql.NullInt64
+	)
+	err = c.db.QueryRow(`SELECT value, expires_at FROM cache_entries WHERE key = ?`, key).Scan(&raw, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = cache.ErrNotFound
+	}
+	if err == nil && expiresAt.Valid && c.clock.Now().UnixNano() >= expiresAt.Int64 {
+		c.expirations.Add(1)
+		c.db.Exec(`DELETE FROM cache_entries WHERE key = ? AND expires_at = ?`, key, expiresAt.Int64)
+		err = cache.ErrNotFound
+	}
+	if err != nil {
+		c.misses.Add(1)
+		return z, err
+	}
+
+	v, err := c.vals.Unmarshal(raw)
+	if err != nil {
+		c.misses.Add(1)
+		return z, err
+	}
+	c.hits.Add(1)
+	return v, nil
+}
+
+func (c *sqliteCache[Key, Val]) Put(k Key, v Val) {
+	if err := c.put(k, v, c.ttl); err != nil {
+		c.logger.Debug("sqlite cache put failed", "key", k, "err", err)
+	}
+}
+
+func (c *sqliteCache[Key, Val]) PutWithTTL(k Key, v Val, d time.Duration) {
+	if err := c.put(k, v, d); err != nil {
+		c.logger.Debug("sqlite cache put failed", "key", k, "err", err)
+	}
+}
+
+func (c *sqliteCache[Key, Val]) put(k Key, v Val, ttl time.Duration) error {
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		return err
+	}
+	val, err := c.vals.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var expiresAt sql.NullInt64
+	if ttl > 0 {
+		expiresAt = sql.NullInt64{Int64: c.clock.Now().Add(ttl).UnixNano(), Valid: true}
+	}
+	_, err = c.db.Exec(`INSERT INTO cache_entries (key, value, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at`, key, val, expiresAt)
+	return err
+}
+
+func (c *sqliteCache[Key, Val]) Delete(k Key) bool {
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		return false
+	}
+	res, err := c.db.Exec(`DELETE FROM cache_entries WHERE key = ?`, key)
+	if err != nil {
+		c.logger.Debug("sqlite cache delete failed", "key", k, "err", err)
+		return false
+	}
+	n, _ := res.RowsAffected()
+	return n > 0
+}
+
+func (c *sqliteCache[Key, Val]) Len() int {
+	n := 0
+	c.db.QueryRow(`SELECT COUNT(*) FROM cache_entries`).Scan(&n)
+	return n
+}
+
+func (c *sqliteCache[Key, Val]) Clear() {
+	if _, err := c.db.Exec(`DELETE FROM cache_entries`); err != nil {
+		c.logger.Debug("sqlite cache clear failed", "err", err)
+	}
+}
+
+func (c *sqliteCache[Key, Val]) Stats() cache.Stats {
+	return cache.Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Expirations: c.expirations.Load(),
+		Size:        c.Len(),
+	}
+}
+
+func (c *sqliteCache[Key, Val]) Cleanup() error {
+	res, err := c.db.Exec(`DELETE FROM cache_entries WHERE expires_at IS NOT NULL AND expires_at <= ?`, c.clock.Now().UnixNano())
+	if err != nil {
+		c.logger.Debug("sqlite cache cleanup failed", "err", err)
+		return err
+	}
+	n, _ := res.RowsAffected()
+	c.expirations.Add(uint64(n))
+	c.logger.Debug("sqlite cache cleanup", "expired", n)
+	return nil
+}
+
+func (c *sqliteCache[Key, Val]) Close() error {
+	return c.db.Close()
+}
@@ -0,0 +1,79 @@
+package replication
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option func(*options)
+
+type options struct {
+	codec   cache.Codec
+	buffer  int
+	backoff time.Duration
+	client  *http.Client
+	logger  *slog.Logger
+}
+
+func WithCodec(codec cache.Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+func WithBuffer(n int) Option {
+	return func(o *options) {
+		o.buffer = n
+	}
+}
+
+func WithBackoff(d time.Duration) Option {
+	return func(o *options) {
+		o.backoff = d
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{
+		codec:   cache.GobCodec,
+		buffer:  1024,
+		backoff: time.Second,
+		client:  http.DefaultClient,
+		logger:  slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.codec == nil {
+		return o, fmt.Errorf("%w: codec must not be nil", cache.ErrInvalidOption)
+	}
+	if o.buffer <= 0 {
+		return o, fmt.Errorf("%w: buffer size must be greater than zero", cache.ErrInvalidOption)
+	}
+	if o.backoff <= 0 {
+		return o, fmt.Errorf("%w: backoff must be greater than zero", cache.ErrInvalidOption)
+	}
+	if o.client == nil {
+		return o, fmt.Errorf("%w: http client must not be nil", cache.ErrInvalidOption)
+	}
+	if o.logger == nil {
+		return o, fmt.Errorf("%w: logger must not be nil", cache.ErrInvalidOption)
+	}
+	return o, nil
+}
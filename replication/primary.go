@@ -0,0 +1,175 @@
+package replication
+
+import (
+	"fmt"
+	"iter"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type source[Key comparable, Val any] interface {
+	Events() <-chan cache.Event[Key, Val]
+	DroppedEvents() uint64
+	All() iter.Seq2[Key, Val]
+}
+
+type subscriber[Key comparable, Val any] struct {
+	records chan record[Key, Val]
+	lagged  chan struct{}
+}
+
+type primary[Key comparable, Val any] struct {
+	source  source[Key, Val]
+	codec   cache.Codec
+	buffer  int
+	logger  *slog.Logger
+	mu      sync.Mutex
+	subs    map[*subscriber[Key, Val]]struct{}
+	closing chan struct{}
+	done    chan struct{}
+}
+
+func NewPrimary[Key comparable, Val any](c source[Key, Val], opts ...Option) (*primary[Key, Val], error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	if c.Events() == nil {
+		return nil, fmt.Errorf("%w: primary cache must be created with events enabled", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	p := &primary[Key, Val]{
+		source:  c,
+		codec:   o.codec,
+		buffer:  o.buffer,
+		logger:  o.logger,
+		subs:    make(map[*subscriber[Key, Val]]struct{}),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+func (p *primary[Key, Val]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sub := &subscriber[Key, Val]{
+		records: make(chan record[Key, Val], p.buffer),
+		lagged:  make(chan struct{}),
+	}
+	p.mu.Lock()
+	p.subs[sub] = struct{}{}
+	p.mu.Unlock()
+	defer p.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	enc := p.codec.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	for k, v := range p.source.All() {
+		if err := enc.Encode(record[Key, Val]{Op: opPut, Key: k, Val: v}); err != nil {
+			return
+		}
+	}
+	if err := enc.Encode(record[Key, Val]{Op: opSynced}); err != nil {
+		return
+	}
+	flush()
+	p.logger.Debug("replica synced", "remote", r.RemoteAddr)
+
+	for {
+		select {
+		case rec := <-sub.records:
+			if err := enc.Encode(rec); err != nil {
+				return
+			}
+			if len(sub.records) == 0 {
+				flush()
+			}
+		case <-sub.lagged:
+			p.logger.Debug("replica lagged, disconnecting", "remote", r.RemoteAddr)
+			return
+		case <-r.Context().Done():
+			return
+		case <-p.closing:
+			return
+		}
+	}
+}
+
+func (p *primary[Key, Val]) Close() error {
+	close(p.closing)
+	<-p.done
+	return nil
+}
+
+func (p *primary[Key, Val]) run() {
+	defer close(p.done)
+	events := p.source.Events()
+	dropped := p.source.DroppedEvents()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if n := p.source.DroppedEvents(); n != dropped {
+				p.logger.Debug("cache dropped events, resyncing replicas", "dropped", n-dropped)
+				dropped = n
+				p.resync()
+			}
+			p.publish(toRecord(ev))
+		case <-p.closing:
+			return
+		}
+	}
+}
+
+func (p *primary[Key, Val]) publish(rec record[Key, Val]) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for sub := range p.subs {
+		select {
+		case sub.records <- rec:
+		default:
+			close(sub.lagged)
+			delete(p.subs, sub)
+		}
+	}
+}
+
+func (p *primary[Key, Val]) resync() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for sub := range p.subs {
+		close(sub.lagged)
+		delete(p.subs, sub)
+	}
+}
+
+func (p *primary[Key, Val]) unsubscribe(sub *subscriber[Key, Val]) {
+	p.mu.Lock()
+	delete(p.subs, sub)
+	p.mu.Unlock()
+}
+
+func toRecord[Key comparable, Val any](ev cache.Event[Key, Val]) record[Key, Val] {
+	switch ev.Type {
+	case cache.EventPut:
+		return record[Key, Val]{Op: opPut, Key: ev.Key, Val: ev.Val}
+	case cache.EventClear:
+		return record[Key, Val]{Op: opClear}
+	}
+	return record[Key, Val]{Op: opDelete, Key: ev.Key}
+}
@@ -0,0 +1,16 @@
+package replication
+
+type op int
+
+const (
+	opPut op = iota + 1
+	opDelete
+	opClear
+	opSynced
+)
+
+type record[Key comparable, Val any] struct {
+	Op  op
+	Key Key
+	Val Val
+}
@@ -0,0 +1,144 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type keyLister[Key comparable] interface {
+	Keys() []Key
+}
+
+type replica[Key comparable, Val any] struct {
+	url     string
+	cache   cache.Cache[Key, Val]
+	codec   cache.Codec
+	backoff time.Duration
+	client  *http.Client
+	logger  *slog.Logger
+	synced  chan struct{}
+	stop    context.CancelFunc
+	done    chan struct{}
+}
+
+func NewReplica[Key comparable, Val any](url string, c cache.Cache[Key, Val], opts ...Option) (*replica[Key, Val], error) {
+	if url == "" {
+		return nil, fmt.Errorf("%w: primary url must not be empty", cache.ErrInvalidOption)
+	}
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	r := &replica[Key, Val]{
+		url:     url,
+		cache:   c,
+		codec:   o.codec,
+		backoff: o.backoff,
+		client:  o.client,
+		logger:  o.logger,
+		synced:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	var ctx context.Context
+	ctx, r.stop = context.WithCancel(context.Background())
+	go r.run(ctx)
+	return r, nil
+}
+
+func (r *replica[Key, Val]) Synced() <-chan struct{} {
+	return r.synced
+}
+
+func (r *replica[Key, Val]) Close() error {
+	r.stop()
+	<-r.done
+	return nil
+}
+
+func (r *replica[Key, Val]) run(ctx context.Context) {
+	defer close(r.done)
+	first := true
+	for {
+		err := r.follow(ctx, &first)
+		if ctx.Err() != nil {
+			return
+		}
+		r.logger.Debug("replication stream ended", "primary", r.url, "err", err)
+		select {
+		case <-time.After(r.backoff):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *replica[Key, Val]) follow(ctx context.Context, first *bool) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("primary %s: %s", r.url, resp.Status)
+	}
+
+	if _, ok := r.cache.(keyLister[Key]); !ok {
+		r.cache.Clear()
+	}
+	dec := r.codec.NewDecoder(resp.Body)
+	seen := make(map[Key]struct{})
+	for {
+		var rec record[Key, Val]
+		if err := dec.Decode(&rec); err != nil {
+			return err
+		}
+		switch rec.Op {
+		case opPut:
+			r.cache.Put(rec.Key, rec.Val)
+			if seen != nil {
+				seen[rec.Key] = struct{}{}
+			}
+		case opDelete:
+			r.cache.Delete(rec.Key)
+			if seen != nil {
+				delete(seen, rec.Key)
+			}
+		case opClear:
+			r.cache.Clear()
+			if seen != nil {
+				clear(seen)
+			}
+		case opSynced:
+			r.sweep(seen)
+			seen = nil
+			if *first {
+				*first = false
+				close(r.synced)
+			}
+		}
+	}
+}
+
+func (r *replica[Key, Val]) sweep(seen map[Key]struct{}) {
+	kl, ok := r.cache.(keyLister[Key])
+	if !ok {
+		return
+	}
+	for _, k := range kl.Keys() {
+		if _, ok := seen[k]; !ok {
+			r.cache.Delete(k)
+		}
+	}
+}
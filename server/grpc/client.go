@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+	"github.com/assaidy/caches/server/grpc/cachepb"
+	grpclib "google.golang.org/grpc"
+)
+
+type client[Key comparable, Val any] struct {
+	rpc     cachepb.CacheClient
+	keys    cache.Serializer[Key]
+	vals    cache.Serializer[Val]
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+var _ cache.Cache[string, any] = (*client[string, any])(nil)
+
+func NewClient[Key comparable, Val any](conn grpclib.ClientConnInterface, opts ...Option[Key, Val]) (*client[Key, Val], error) {
+	if conn == nil {
+		return nil, fmt.Errorf("%w: connection must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &client[Key, Val]{
+		rpc:     cachepb.NewCacheClient(conn),
+		keys:    o.keys,
+		vals:    o.vals,
+		timeout: o.timeout,
+		logger:  o.logger,
+	}, nil
+}
+
+func (c *client[Key, Val]) Get(k Key) (Val, bool) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	v, err := c.GetContext(ctx, k)
+	return v, err == nil
+}
+
+func (c *client[Key, Val]) GetContext(ctx context.Context, k Key) (Val, error) {
+	var z Val
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		return z, err
+	}
+	resp, err := c.rpc.Get(ctx, &cachepb.GetRequest{Key: key})
+	if err != nil {
+		return z, err
+	}
+	if !resp.GetFound() {
+		return z, cache.ErrNotFound
+	}
+	return c.vals.Unmarshal(resp.GetValue())
+}
+
+func (c *client[Key, Val]) Put(k Key, v Val) {
+	c.PutWithTTL(k, v, 0)
+}
+
+func (c *client[Key, Val]) PutWithTTL(k Key, v Val, d time.Duration) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if err := c.PutContext(ctx, k, v, d); err != nil {
+		c.logger.Debug("grpc cache put failed", "key", k, "err", err)
+	}
+}
+
+func (c *client[Key, Val]) PutContext(ctx context.Context, k Key, v Val, ttl time.Duration) error {
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		return err
+	}
+	val, err := c.vals.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = c.rpc.Put(ctx, &cachepb.PutRequest{Key: key, Value: val, TtlMillis: ttlMillis(ttl)})
+	return err
+}
+
+func (c *client[Key, Val]) Delete(k Key) bool {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	key, err := c.keys.Marshal(k)
+	if err != nil {
+		return false
+	}
+	resp, err := c.rpc.Delete(ctx, &cachepb.DeleteRequest{Key: key})
+	if err != nil {
+		c.logger.Debug("grpc cache delete failed", "key", k, "err", err)
+		return false
+	}
+	return resp.GetDeleted()
+}
+
+func (c *client[Key, Val]) Len() int {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.rpc.Len(ctx, &cachepb.LenRequest{})
+	if err != nil {
+		c.logger.Debug("grpc cache len failed", "err", err)
+		return 0
+	}
+	return int(resp.GetLen())
+}
+
+func (c *client[Key, Val]) Clear() {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if _, err := c.rpc.Clear(ctx, &cachepb.ClearRequest{}); err != nil {
+		c.logger.Debug("grpc cache clear failed", "err", err)
+	}
+}
+
+func (c *client[Key, Val]) Stats() cache.Stats {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	resp, err := c.rpc.Stats(ctx, &cachepb.StatsRequest{})
+	if err != nil {
+		c.logger.Debug("grpc cache stats failed", "err", err)
+		return cache.Stats{}
+	}
+	return cache.Stats{
+		Hits:          resp.GetHits(),
+		Misses:        resp.GetMisses(),
+		Evictions:     resp.GetEvictions(),
+		Expirations:   resp.GetExpirations(),
+		Loads:         resp.GetLoads(),
+		LoadFailures:  resp.GetLoadFailures(),
+		DroppedEvents: resp.GetDroppedEvents(),
+		Size:          int(resp.GetSize()),
+	}
+}
+
+func (c *client[Key, Val]) context() (context.Context, context.CancelFunc) {
+	if c.timeout > 0 {
+		return context.WithTimeout(context.Background(), c.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func ttlMillis(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return int64((ttl + time.Millisecond - 1) / time.Millisecond)
+}
@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+	"github.com/assaidy/caches/server/grpc/cachepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ttlPutter[Key comparable, Val any] interface {
+	PutWithTTL(k Key, v Val, d time.Duration)
+}
+
+type server[Key comparable, Val any] struct {
+	cachepb.UnimplementedCacheServer
+	cache cache.Cache[Key, Val]
+	keys  cache.Serializer[Key]
+	vals  cache.Serializer[Val]
+}
+
+func NewServer[Key comparable, Val any](c cache.Cache[Key, Val], opts ...Option[Key, Val]) (*server[Key, Val], error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &server[Key, Val]{cache: c, keys: o.keys, vals: o.vals}, nil
+}
+
+func (s *server[Key, Val]) Get(_ context.Context, req *cachepb.GetRequest) (*cachepb.GetResponse, error) {
+	k, err := s.key(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	v, ok := s.cache.Get(k)
+	if !ok {
+		return &cachepb.GetResponse{}, nil
+	}
+	val, err := s.vals.Marshal(v)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encode value: %v", err)
+	}
+	return &cachepb.GetResponse{Found: true, Value: val}, nil
+}
+
+func (s *server[Key, Val]) Put(_ context.Context, req *cachepb.PutRequest) (*cachepb.PutResponse, error) {
+	k, err := s.key(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.vals.Unmarshal(req.GetValue())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decode value: %v", err)
+	}
+	if ttl := time.Duration(req.GetTtlMillis()) * time.Millisecond; ttl > 0 {
+		tp, ok := s.cache.(ttlPutter[Key, Val])
+		if !ok {
+			return nil, status.Error(codes.Unimplemented, "cache does not support per-entry ttl")
+		}
+		tp.PutWithTTL(k, v, ttl)
+	} else {
+		s.cache.Put(k, v)
+	}
+	return &cachepb.PutResponse{}, nil
+}
+
+func (s *server[Key, Val]) Delete(_ context.Context, req *cachepb.DeleteRequest) (*cachepb.DeleteResponse, error) {
+	k, err := s.key(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	return &cachepb.DeleteResponse{Deleted: s.cache.Delete(k)}, nil
+}
+
+func (s *server[Key, Val]) Len(context.Context, *cachepb.LenRequest) (*cachepb.LenResponse, error) {
+	return &cachepb.LenResponse{Len: int64(s.cache.Len())}, nil
+}
+
+func (s *server[Key, Val]) Clear(context.Context, *cachepb.ClearRequest) (*cachepb.ClearResponse, error) {
+	s.cache.Clear()
+	return &cachepb.ClearResponse{}, nil
+}
+
+func (s *server[Key, Val]) Stats(context.Context, *cachepb.StatsRequest) (*cachepb.StatsResponse, error) {
+	st := s.cache.Stats()
+	return &cachepb.StatsResponse{
+		Hits:          st.Hits,
+		Misses:        st.Misses,
+		Evictions:     st.Evictions,
+		Expirations:   st.Expirations,
+		Loads:         st.Loads,
+		LoadFailures:  st.LoadFailures,
+		DroppedEvents: st.DroppedEvents,
+		Size:          int64(st.Size),
+	}, nil
+}
+
+func (s *server[Key, Val]) key(b []byte) (Key, error) {
+	k, err := s.keys.Unmarshal(b)
+	if err != nil {
+		return k, status.Errorf(codes.InvalidArgument, "decode key: %v", err)
+	}
+	return k, nil
+}
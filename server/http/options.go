@@ -0,0 +1,43 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option[Val any] func(*options[Val])
+
+type options[Val any] struct {
+	vals   cache.Serializer[Val]
+	logger *slog.Logger
+}
+
+func WithSerializer[Val any](ser cache.Serializer[Val]) Option[Val] {
+	return func(o *options[Val]) {
+		o.vals = ser
+	}
+}
+
+func WithLogger[Val any](logger *slog.Logger) Option[Val] {
+	return func(o *options[Val]) {
+		o.logger = logger
+	}
+}
+
+func applyOptions[Val any](opts []Option[Val]) (options[Val], error) {
+	o := options[Val]{
+		logger: slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger == nil {
+		return o, fmt.Errorf("%w: logger must not be nil", cache.ErrInvalidOption)
+	}
+	if o.vals == nil {
+		o.vals, _ = cache.NewSerializer[Val](cache.GobCodec)
+	}
+	return o, nil
+}
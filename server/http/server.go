@@ -0,0 +1,163 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	nethttp "net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+const maxBodySize = 32 << 20
+
+type ttlPutter[Val any] interface {
+	PutWithTTL(k string, v Val, d time.Duration)
+}
+
+type keyLister interface {
+	Keys() []string
+}
+
+type dumper interface {
+	Dump(w io.Writer, limit int) error
+}
+
+type server[Val any] struct {
+	cache  cache.Cache[string, Val]
+	vals   cache.Serializer[Val]
+	logger *slog.Logger
+	mux    *nethttp.ServeMux
+}
+
+func NewServer[Val any](c cache.Cache[string, Val], opts ...Option[Val]) (*server[Val], error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	s := &server[Val]{cache: c, vals: o.vals, logger: o.logger, mux: nethttp.NewServeMux()}
+	s.mux.HandleFunc("GET /keys/{key...}", s.get)
+	s.mux.HandleFunc("PUT /keys/{key...}", s.put)
+	s.mux.HandleFunc("DELETE /keys/{key...}", s.delete)
+	s.mux.HandleFunc("GET /keys", s.keys)
+	s.mux.HandleFunc("GET /stats", s.stats)
+	s.mux.HandleFunc("GET /dump", s.dump)
+	return s, nil
+}
+
+func (s *server[Val]) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *server[Val]) get(w nethttp.ResponseWriter, r *nethttp.Request) {
+	v, ok := s.cache.Get(r.PathValue("key"))
+	if !ok {
+		nethttp.NotFound(w, r)
+		return
+	}
+	body, err := s.vals.Marshal(v)
+	if err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(body)
+}
+
+func (s *server[Val]) put(w nethttp.ResponseWriter, r *nethttp.Request) {
+	var ttl time.Duration
+	if q := r.URL.Query().Get("ttl"); q != "" {
+		d, err := time.ParseDuration(q)
+		if err != nil || d <= 0 {
+			nethttp.Error(w, "invalid ttl", nethttp.StatusBadRequest)
+			return
+		}
+		ttl = d
+	}
+	body, err := io.ReadAll(nethttp.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusRequestEntityTooLarge)
+		return
+	}
+	v, err := s.vals.Unmarshal(body)
+	if err != nil {
+		nethttp.Error(w, err.Error(), nethttp.StatusBadRequest)
+		return
+	}
+
+	k := r.PathValue("key")
+	if ttl > 0 {
+		tp, ok := s.cache.(ttlPutter[Val])
+		if !ok {
+			nethttp.Error(w, "cache does not support per-entry ttl", nethttp.StatusNotImplemented)
+			return
+		}
+		tp.PutWithTTL(k, v, ttl)
+	} else {
+		s.cache.Put(k, v)
+	}
+	s.logger.Debug("http cache put", "key", k, "ttl", ttl)
+	w.WriteHeader(nethttp.StatusNoContent)
+}
+
+func (s *server[Val]) delete(w nethttp.ResponseWriter, r *nethttp.Request) {
+	if !s.cache.Delete(r.PathValue("key")) {
+		nethttp.NotFound(w, r)
+		return
+	}
+	w.WriteHeader(nethttp.StatusNoContent)
+}
+
+func (s *server[Val]) keys(w nethttp.ResponseWriter, r *nethttp.Request) {
+	kl, ok := s.cache.(keyLister)
+	if !ok {
+		nethttp.Error(w, "cache does not support listing keys", nethttp.StatusNotImplemented)
+		return
+	}
+	prefix := r.URL.Query().Get("prefix")
+	keys := slices.DeleteFunc(kl.Keys(), func(k string) bool {
+		return !strings.HasPrefix(k, prefix)
+	})
+	slices.Sort(keys)
+	writeJSON(w, keys)
+}
+
+func (s *server[Val]) stats(w nethttp.ResponseWriter, r *nethttp.Request) {
+	writeJSON(w, s.cache.Stats())
+}
+
+func (s *server[Val]) dump(w nethttp.ResponseWriter, r *nethttp.Request) {
+	d, ok := s.cache.(dumper)
+	if !ok {
+		nethttp.Error(w, "cache does not support dumping", nethttp.StatusNotImplemented)
+		return
+	}
+	limit := 0
+	if q := r.URL.Query().Get("limit"); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil || n < 0 {
+			nethttp.Error(w, "invalid limit", nethttp.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := d.Dump(w, limit); err != nil {
+		s.logger.Debug("http cache dump failed", "err", err)
+	}
+}
+
+func writeJSON(w nethttp.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
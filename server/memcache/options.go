@@ -0,0 +1,66 @@
+package memcache
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option func(*options)
+
+type options struct {
+	maxItemSize int
+	maxLineSize int
+	logger      *slog.Logger
+	clock       cache.Clock
+}
+
+func WithMaxItemSize(n int) Option {
+	return func(o *options) {
+		o.maxItemSize = n
+	}
+}
+
+func WithMaxLineSize(n int) Option {
+	return func(o *options) {
+		o.maxLineSize = n
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func WithClock(clock cache.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{
+		maxItemSize: 1 << 20,
+		maxLineSize: 1 << 16,
+		logger:      slog.New(slog.DiscardHandler),
+		clock:       cache.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxItemSize <= 0 {
+		return o, fmt.Errorf("%w: max item size must be greater than zero", cache.ErrInvalidOption)
+	}
+	if o.maxLineSize <= 0 {
+		return o, fmt.Errorf("%w: max line size must be greater than zero", cache.ErrInvalidOption)
+	}
+	if o.logger == nil {
+		return o, fmt.Errorf("%w: logger must not be nil", cache.ErrInvalidOption)
+	}
+	if o.clock == nil {
+		return o, fmt.Errorf("%w: clock must not be nil", cache.ErrInvalidOption)
+	}
+	return o, nil
+}
@@ -0,0 +1,503 @@
+package memcache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+const (
+	maxKeyLength    = 250
+	relativeTTLSpan = 30 * 24 * 60 * 60
+	version         = "1.6.0-caches"
+)
+
+var (
+	errClient      = errors.New("bad command line format")
+	errLineTooLong = errors.New("command line too long")
+)
+
+type Item struct {
+	Value []byte
+	Flags uint32
+	CAS   uint64
+}
+
+type ttlPutter interface {
+	PutWithTTL(k string, v Item, d time.Duration)
+}
+
+type server struct {
+	cache       cache.Cache[string, Item]
+	maxItemSize int
+	maxLineSize int
+	logger      *slog.Logger
+	clock       cache.Clock
+	mu          sync.Mutex
+	cas         uint64
+	started     time.Time
+	connMu      sync.Mutex
+	listeners   map[net.Listener]struct{}
+	conns       map[net.Conn]struct{}
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+func NewServer(c cache.Cache[string, Item], opts ...Option) (*server, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &server{
+		cache:       c,
+		maxItemSize: o.maxItemSize,
+		maxLineSize: o.maxLineSize,
+		logger:      o.logger,
+		clock:       o.clock,
+		started:     o.clock.Now(),
+		listeners:   make(map[net.Listener]struct{}),
+		conns:       make(map[net.Conn]struct{}),
+	}, nil
+}
+
+func (s *server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+func (s *server) Serve(l net.Listener) error {
+	s.connMu.Lock()
+	if s.closed {
+		s.connMu.Unlock()
+		l.Close()
+		return net.ErrClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.connMu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.connMu.Lock()
+			closed := s.closed
+			delete(s.listeners, l)
+			s.connMu.Unlock()
+			if closed {
+				return net.ErrClosed
+			}
+			return err
+		}
+		s.connMu.Lock()
+		if s.closed {
+			s.connMu.Unlock()
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.connMu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+func (s *server) Close() error {
+	s.connMu.Lock()
+	s.closed = true
+	var err error
+	for l := range s.listeners {
+		err = errors.Join(err, l.Close())
+	}
+	for c := range s.conns {
+		c.Close()
+	}
+	s.connMu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *server) serveConn(conn net.Conn) {
+	defer func() {
+		s.connMu.Lock()
+		delete(s.conns, conn)
+		s.connMu.Unlock()
+		conn.Close()
+		s.wg.Done()
+	}()
+
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	for {
+		line, err := s.readLine(rw.Reader)
+		if errors.Is(err, errLineTooLong) {
+			rw.WriteString("CLIENT_ERROR line too long\r\n")
+			rw.Flush()
+			return
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				s.logger.Debug("memcache connection error", "remote", conn.RemoteAddr(), "err", err)
+			}
+			return
+		}
+		fields := strings.Fields(string(line))
+		if len(fields) == 0 {
+			rw.WriteString("ERROR\r\n")
+		} else if quit := s.dispatch(rw, fields); quit {
+			rw.Flush()
+			return
+		}
+		if rw.Reader.Buffered() == 0 {
+			if err := rw.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *server) dispatch(rw *bufio.ReadWriter, f []string) bool {
+	var err error
+	switch cmd := f[0]; cmd {
+	case "get", "gets":
+		err = s.get(rw.Writer, f[1:], cmd == "gets")
+	case "set", "add", "replace", "append", "prepend", "cas":
+		err = s.store(rw, cmd, f[1:])
+	case "delete":
+		err = s.delete(rw.Writer, f[1:])
+	case "incr", "decr":
+		err = s.incr(rw.Writer, f[1:], cmd == "decr")
+	case "touch":
+		err = s.touch(rw.Writer, f[1:])
+	case "flush_all":
+		err = s.flush(rw.Writer, f[1:])
+	case "stats":
+		s.stats(rw.Writer)
+	case "version":
+		rw.WriteString("VERSION " + version + "\r\n")
+	case "quit":
+		return true
+	default:
+		rw.WriteString("ERROR\r\n")
+	}
+	if err != nil {
+		if errors.Is(err, errClient) {
+			fmt.Fprintf(rw, "CLIENT_ERROR %v\r\n", err)
+		} else {
+			fmt.Fprintf(rw, "SERVER_ERROR %v\r\n", err)
+		}
+	}
+	return false
+}
+
+func (s *server) get(w *bufio.Writer, keys []string, withCAS bool) error {
+	if len(keys) == 0 {
+		return errClient
+	}
+	for _, k := range keys {
+		it, ok := s.cache.Get(k)
+		if !ok {
+			continue
+		}
+		if withCAS {
+			fmt.Fprintf(w, "VALUE %s %d %d %d\r\n", k, it.Flags, len(it.Value), it.CAS)
+		} else {
+			fmt.Fprintf(w, "VALUE %s %d %d\r\n", k, it.Flags, len(it.Value))
+		}
+		w.Write(it.Value)
+		w.WriteString("\r\n")
+	}
+	w.WriteString("END\r\n")
+	return nil
+}
+
+func (s *server) store(rw *bufio.ReadWriter, cmd string, args []string) error {
+	want := 4
+	if cmd == "cas" {
+		want = 5
+	}
+	if len(args) != want && len(args) != want+1 {
+		return errClient
+	}
+	noreply := len(args) == want+1 && args[want] == "noreply"
+	k := args[0]
+	flags, err1 := strconv.ParseUint(args[1], 10, 32)
+	exptime, err2 := strconv.ParseInt(args[2], 10, 64)
+	size, err3 := strconv.Atoi(args[3])
+	if err := errors.Join(err1, err2, err3); err != nil || size < 0 || !validKey(k) {
+		return errClient
+	}
+	var unique uint64
+	if cmd == "cas" {
+		u, err := strconv.ParseUint(args[4], 10, 64)
+		if err != nil {
+			return errClient
+		}
+		unique = u
+	}
+
+	if size > s.maxItemSize {
+		if _, err := rw.Discard(size + 2); err != nil {
+			return err
+		}
+		return errors.New("object too large for cache")
+	}
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(rw, data); err != nil {
+		return err
+	}
+	if string(data[size:]) != "\r\n" {
+		return fmt.Errorf("%w: bad data chunk", errClient)
+	}
+	data = data[:size]
+
+	ttl, expired := s.ttl(exptime)
+	reply := s.apply(cmd, k, Item{Value: data, Flags: uint32(flags)}, unique, ttl, expired)
+	if !noreply {
+		rw.WriteString(reply + "\r\n")
+	}
+	return nil
+}
+
+func (s *server) apply(cmd, k string, it Item, unique uint64, ttl time.Duration, expired bool) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, exists := s.cache.Get(k)
+	switch cmd {
+	case "add":
+		if exists {
+			return "NOT_STORED"
+		}
+	case "replace":
+		if !exists {
+			return "NOT_STORED"
+		}
+	case "append", "prepend":
+		if !exists {
+			return "NOT_STORED"
+		}
+		if cmd == "append" {
+			it.Value = append(append([]byte(nil), old.Value...), it.Value...)
+		} else {
+			it.Value = append(it.Value, old.Value...)
+		}
+		it.Flags = old.Flags
+	case "cas":
+		if !exists {
+			return "NOT_FOUND"
+		}
+		if old.CAS != unique {
+			return "EXISTS"
+		}
+	}
+	if expired {
+		s.cache.Delete(k)
+		return "STORED"
+	}
+	if err := s.put(k, it, ttl); err != nil {
+		return "SERVER_ERROR " + err.Error()
+	}
+	return "STORED"
+}
+
+func (s *server) put(k string, it Item, ttl time.Duration) error {
+	s.cas++
+	it.CAS = s.cas
+	if ttl > 0 {
+		tp, ok := s.cache.(ttlPutter)
+		if !ok {
+			return errors.New("cache does not support per-entry ttl")
+		}
+		tp.PutWithTTL(k, it, ttl)
+		return nil
+	}
+	s.cache.Put(k, it)
+	return nil
+}
+
+func (s *server) delete(w *bufio.Writer, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errClient
+	}
+	noreply := len(args) == 2 && args[1] == "noreply"
+	s.mu.Lock()
+	deleted := s.cache.Delete(args[0])
+	s.mu.Unlock()
+	if noreply {
+		return nil
+	}
+	if deleted {
+		w.WriteString("DELETED\r\n")
+	} else {
+		w.WriteString("NOT_FOUND\r\n")
+	}
+	return nil
+}
+
+func (s *server) incr(w *bufio.Writer, args []string, decr bool) error {
+	if len(args) != 2 && len(args) != 3 {
+		return errClient
+	}
+	noreply := len(args) == 3 && args[2] == "noreply"
+	delta, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid numeric delta argument", errClient)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, ok := s.cache.Get(args[0])
+	if !ok {
+		if !noreply {
+			w.WriteString("NOT_FOUND\r\n")
+		}
+		return nil
+	}
+	n, err := strconv.ParseUint(string(it.Value), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: cannot increment or decrement non-numeric value", errClient)
+	}
+	switch {
+	case !decr:
+		n += delta
+	case delta > n:
+		n = 0
+	default:
+		n -= delta
+	}
+	s.cas++
+	s.cache.Put(args[0], Item{Value: strconv.AppendUint(nil, n, 10), Flags: it.Flags, CAS: s.cas})
+	if !noreply {
+		fmt.Fprintf(w, "%d\r\n", n)
+	}
+	return nil
+}
+
+func (s *server) touch(w *bufio.Writer, args []string) error {
+	if len(args) != 2 && len(args) != 3 {
+		return errClient
+	}
+	noreply := len(args) == 3 && args[2] == "noreply"
+	exptime, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return errClient
+	}
+	ttl, expired := s.ttl(exptime)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, ok := s.cache.Get(args[0])
+	if ok {
+		if expired {
+			s.cache.Delete(args[0])
+		} else if err := s.put(args[0], it, ttl); err != nil {
+			return err
+		}
+	}
+	if noreply {
+		return nil
+	}
+	if ok {
+		w.WriteString("TOUCHED\r\n")
+	} else {
+		w.WriteString("NOT_FOUND\r\n")
+	}
+	return nil
+}
+
+func (s *server) flush(w *bufio.Writer, args []string) error {
+	noreply := len(args) > 0 && args[len(args)-1] == "noreply"
+	if noreply {
+		args = args[:len(args)-1]
+	}
+	if len(args) > 1 {
+		return errClient
+	}
+	var delay int64
+	if len(args) == 1 {
+		d, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || d < 0 {
+			return errClient
+		}
+		delay = d
+	}
+	if delay > 0 {
+		time.AfterFunc(time.Duration(delay)*time.Second, s.cache.Clear)
+	} else {
+		s.cache.Clear()
+	}
+	if !noreply {
+		w.WriteString("OK\r\n")
+	}
+	return nil
+}
+
+func (s *server) stats(w *bufio.Writer) {
+	st := s.cache.Stats()
+	now := s.clock.Now()
+	fmt.Fprintf(w, "STAT uptime %d\r\n", int64(now.Sub(s.started).Seconds()))
+	fmt.Fprintf(w, "STAT time %d\r\n", now.Unix())
+	fmt.Fprintf(w, "STAT version %s\r\n", version)
+	fmt.Fprintf(w, "STAT curr_items %d\r\n", st.Size)
+	fmt.Fprintf(w, "STAT get_hits %d\r\n", st.Hits)
+	fmt.Fprintf(w, "STAT get_misses %d\r\n", st.Misses)
+	fmt.Fprintf(w, "STAT evictions %d\r\n", st.Evictions)
+	fmt.Fprintf(w, "STAT expired_unfetched %d\r\n", st.Expirations)
+	w.WriteString("END\r\n")
+}
+
+func (s *server) ttl(exptime int64) (time.Duration, bool) {
+	switch {
+	case exptime == 0:
+		return 0, false
+	case exptime < 0:
+		return 0, true
+	case exptime > relativeTTLSpan:
+		d := time.Unix(exptime, 0).Sub(s.clock.Now())
+		return d, d <= 0
+	}
+	return time.Duration(exptime) * time.Second, false
+}
+
+func validKey(k string) bool {
+	if len(k) == 0 || len(k) > maxKeyLength {
+		return false
+	}
+	for i := 0; i < len(k); i++ {
+		if k[i] < ' ' || k[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *server) readLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line)+len(chunk) > s.maxLineSize {
+			return nil, errLineTooLong
+		}
+		line = append(line, chunk...)
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return line, err
+		}
+	}
+}
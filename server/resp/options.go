@@ -0,0 +1,44 @@
+package resp
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option func(*options)
+
+type options struct {
+	maxBulkSize int
+	logger      *slog.Logger
+}
+
+func WithMaxBulkSize(n int) Option {
+	return func(o *options) {
+		o.maxBulkSize = n
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{
+		maxBulkSize: 512 << 20,
+		logger:      slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxBulkSize <= 0 {
+		return o, fmt.Errorf("%w: max bulk size must be greater than zero", cache.ErrInvalidOption)
+	}
+	if o.logger == nil {
+		return o, fmt.Errorf("%w: logger must not be nil", cache.ErrInvalidOption)
+	}
+	return o, nil
+}
@@ -0,0 +1,420 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+const defaultScanCount = 10
+
+type ttlStore interface {
+	cache.Cache[string, []byte]
+	PutWithTTL(k string, v []byte, d time.Duration)
+	GetWithExpiry(k string) ([]byte, time.Time, bool)
+	Expire(k string, d time.Duration) bool
+	Persist(k string) bool
+	Keys() []string
+}
+
+type server struct {
+	cache       ttlStore
+	maxBulkSize int
+	logger      *slog.Logger
+	mu          sync.Mutex
+	connMu      sync.Mutex
+	listeners   map[net.Listener]struct{}
+	conns       map[net.Conn]struct{}
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+func NewServer(c ttlStore, opts ...Option) (*server, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &server{
+		cache:       c,
+		maxBulkSize: o.maxBulkSize,
+		logger:      o.logger,
+		listeners:   make(map[net.Listener]struct{}),
+		conns:       make(map[net.Conn]struct{}),
+	}, nil
+}
+
+func (s *server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+func (s *server) Serve(l net.Listener) error {
+	s.connMu.Lock()
+	if s.closed {
+		s.connMu.Unlock()
+		l.Close()
+		return net.ErrClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.connMu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.connMu.Lock()
+			closed := s.closed
+			delete(s.listeners, l)
+			s.connMu.Unlock()
+			if closed {
+				return net.ErrClosed
+			}
+			return err
+		}
+		s.connMu.Lock()
+		if s.closed {
+			s.connMu.Unlock()
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.connMu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+func (s *server) Close() error {
+	s.connMu.Lock()
+	s.closed = true
+	var err error
+	for l := range s.listeners {
+		err = errors.Join(err, l.Close())
+	}
+	for c := range s.conns {
+		c.Close()
+	}
+	s.connMu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *server) serveConn(conn net.Conn) {
+	defer func() {
+		s.connMu.Lock()
+		delete(s.conns, conn)
+		s.connMu.Unlock()
+		conn.Close()
+		s.wg.Done()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := writer{bufio.NewWriter(conn)}
+	for {
+		args, err := readCommand(r, s.maxBulkSize)
+		if errors.Is(err, errProtocol) {
+			w.error("ERR Protocol error")
+			w.Flush()
+			return
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				s.logger.Debug("resp connection error", "remote", conn.RemoteAddr(), "err", err)
+			}
+			return
+		}
+		if len(args) > 0 {
+			if quit := s.dispatch(w, args); quit {
+				w.Flush()
+				return
+			}
+		}
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *server) dispatch(w writer, args [][]byte) bool {
+	cmd := strings.ToUpper(string(args[0]))
+	switch cmd {
+	case "PING":
+		if len(args) > 1 {
+			w.bulk(args[1])
+		} else {
+			w.simple("PONG")
+		}
+	case "ECHO":
+		if s.arity(w, cmd, args, 2, 2) {
+			w.bulk(args[1])
+		}
+	case "GET":
+		if s.arity(w, cmd, args, 2, 2) {
+			if v, ok := s.cache.Get(string(args[1])); ok {
+				w.bulk(v)
+			} else {
+				w.null()
+			}
+		}
+	case "SET":
+		if s.arity(w, cmd, args, 3, -1) {
+			s.set(w, args[1:])
+		}
+	case "DEL", "UNLINK":
+		if s.arity(w, cmd, args, 2, -1) {
+			var n int64
+			for _, k := range args[1:] {
+				if s.cache.Delete(string(k)) {
+					n++
+				}
+			}
+			w.integer(n)
+		}
+	case "EXISTS":
+		if s.arity(w, cmd, args, 2, -1) {
+			var n int64
+			for _, k := range args[1:] {
+				if _, _, ok := s.cache.GetWithExpiry(string(k)); ok {
+					n++
+				}
+			}
+			w.integer(n)
+		}
+	case "EXPIRE", "PEXPIRE":
+		if s.arity(w, cmd, args, 3, 3) {
+			n, err := strconv.ParseInt(string(args[2]), 10, 64)
+			if err != nil {
+				w.error("ERR value is not an integer or out of range")
+				break
+			}
+			unit := time.Second
+			if cmd == "PEXPIRE" {
+				unit = time.Millisecond
+			}
+			w.integer(boolInt(s.cache.Expire(string(args[1]), time.Duration(n)*unit)))
+		}
+	case "PERSIST":
+		if s.arity(w, cmd, args, 2, 2) {
+			w.integer(boolInt(s.cache.Persist(string(args[1]))))
+		}
+	case "TTL", "PTTL":
+		if s.arity(w, cmd, args, 2, 2) {
+			w.integer(s.ttl(string(args[1]), cmd == "PTTL"))
+		}
+	case "SCAN":
+		if s.arity(w, cmd, args, 2, -1) {
+			s.scan(w, args[1:])
+		}
+	case "KEYS":
+		if s.arity(w, cmd, args, 2, 2) {
+			keys := s.matching(string(args[1]))
+			w.array(len(keys))
+			for _, k := range keys {
+				w.bulk([]byte(k))
+			}
+		}
+	case "DBSIZE":
+		w.integer(int64(s.cache.Len()))
+	case "FLUSHDB", "FLUSHALL":
+		s.cache.Clear()
+		w.simple("OK")
+	case "SELECT":
+		if s.arity(w, cmd, args, 2, 2) {
+			if string(args[1]) == "0" {
+				w.simple("OK")
+			} else {
+				w.error("ERR DB index is out of range")
+			}
+		}
+	case "CLIENT":
+		w.simple("OK")
+	case "COMMAND":
+		w.array(0)
+	case "QUIT":
+		w.simple("OK")
+		return true
+	default:
+		w.error(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+	}
+	return false
+}
+
+func (s *server) arity(w writer, cmd string, args [][]byte, lo, hi int) bool {
+	if len(args) < lo || (hi >= 0 && len(args) > hi) {
+		w.error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(cmd)))
+		return false
+	}
+	return true
+}
+
+func (s *server) set(w writer, args [][]byte) {
+	k, v := string(args[0]), args[1]
+	var (
+		ttl       time.Duration
+		nx, xx    bool
+		keepTTL   bool
+		returnOld bool
+	)
+	for i := 2; i < len(args); i++ {
+		switch opt := strings.ToUpper(string(args[i])); opt {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "GET":
+			returnOld = true
+		case "KEEPTTL":
+			keepTTL = true
+		case "EX", "PX":
+			if i+1 == len(args) {
+				w.error("ERR syntax error")
+				return
+			}
+			n, err := strconv.ParseInt(string(args[i+1]), 10, 64)
+			if err != nil || n <= 0 {
+				w.error("ERR invalid expire time in 'set' command")
+				return
+			}
+			unit := time.Second
+			if opt == "PX" {
+				unit = time.Millisecond
+			}
+			ttl = time.Duration(n) * unit
+			i++
+		default:
+			w.error("ERR syntax error")
+			return
+		}
+	}
+	if nx && xx || keepTTL && ttl > 0 {
+		w.error("ERR syntax error")
+		return
+	}
+
+	s.mu.Lock()
+	old, expiresAt, exists := s.cache.GetWithExpiry(k)
+	stored := !(nx && exists) && !(xx && !exists)
+	if stored {
+		if keepTTL && exists && !expiresAt.IsZero() {
+			ttl = time.Until(expiresAt)
+		}
+		s.cache.PutWithTTL(k, v, ttl)
+		if keepTTL && exists && expiresAt.IsZero() {
+			s.cache.Persist(k)
+		}
+	}
+	s.mu.Unlock()
+
+	switch {
+	case returnOld && exists:
+		w.bulk(old)
+	case returnOld, !stored:
+		w.null()
+	default:
+		w.simple("OK")
+	}
+}
+
+func (s *server) ttl(k string, millis bool) int64 {
+	_, expiresAt, ok := s.cache.GetWithExpiry(k)
+	switch {
+	case !ok:
+		return -2
+	case expiresAt.IsZero():
+		return -1
+	}
+	d := time.Until(expiresAt)
+	if millis {
+		return d.Milliseconds()
+	}
+	return int64((d + time.Second/2) / time.Second)
+}
+
+func (s *server) scan(w writer, args [][]byte) {
+	cursor, err := strconv.Atoi(string(args[0]))
+	if err != nil || cursor < 0 {
+		w.error("ERR invalid cursor")
+		return
+	}
+	pattern, count := "*", defaultScanCount
+	for i := 1; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			w.error("ERR syntax error")
+			return
+		}
+		switch strings.ToUpper(string(args[i])) {
+		case "MATCH":
+			pattern = string(args[i+1])
+		case "COUNT":
+			n, err := strconv.Atoi(string(args[i+1]))
+			if err != nil || n <= 0 {
+				w.error("ERR value is not an integer or out of range")
+				return
+			}
+			count = n
+		case "TYPE":
+			if strings.ToLower(string(args[i+1])) != "string" {
+				cursor = -1
+			}
+		default:
+			w.error("ERR syntax error")
+			return
+		}
+	}
+
+	keys := s.cache.Keys()
+	slices.Sort(keys)
+	var page []string
+	next := 0
+	if cursor >= 0 && cursor < len(keys) {
+		end := min(cursor+count, len(keys))
+		for _, k := range keys[cursor:end] {
+			if match(pattern, k) {
+				page = append(page, k)
+			}
+		}
+		if end < len(keys) {
+			next = end
+		}
+	}
+	w.array(2)
+	w.bulk([]byte(strconv.Itoa(next)))
+	w.array(len(page))
+	for _, k := range page {
+		w.bulk([]byte(k))
+	}
+}
+
+func (s *server) matching(pattern string) []string {
+	keys := slices.DeleteFunc(s.cache.Keys(), func(k string) bool {
+		return !match(pattern, k)
+	})
+	slices.Sort(keys)
+	return keys
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+package sessionstore
+
+import (
+	"bytes"
+	"encoding/base32"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type gorillaStore struct {
+	cache  ttlStore
+	prefix string
+	codec  cache.Codec
+	cookie sessions.Options
+	codecs []securecookie.Codec
+}
+
+func NewGorillaStore(c ttlStore, opts ...Option) (*gorillaStore, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.keyPairs) == 0 {
+		return nil, fmt.Errorf("%w: at least one key pair is required", cache.ErrInvalidOption)
+	}
+	codecs := securecookie.CodecsFromPairs(o.keyPairs...)
+	for _, sc := range codecs {
+		if sc, ok := sc.(*securecookie.SecureCookie); ok {
+			sc.MaxAge(o.cookie.MaxAge)
+		}
+	}
+	return &gorillaStore{
+		cache:  c,
+		prefix: o.prefix,
+		codec:  o.codec,
+		cookie: o.cookie,
+		codecs: codecs,
+	}, nil
+}
+
+func (s *gorillaStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+func (s *gorillaStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := s.cookie
+	session.Options = &opts
+	session.IsNew = true
+
+	c, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	if err := securecookie.DecodeMulti(name, c.Value, &session.ID, s.codecs...); err != nil {
+		return session, err
+	}
+	b, ok := s.cache.Get(s.prefix + session.ID)
+	if !ok {
+		return session, nil
+	}
+	if err := s.codec.NewDecoder(bytes.NewReader(b)).Decode(&session.Values); err != nil {
+		return session, err
+	}
+	session.IsNew = false
+	return session, nil
+}
+
+func (s *gorillaStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			s.cache.Delete(s.prefix + session.ID)
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+	if session.ID == "" {
+		session.ID = strings.TrimRight(base32.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32)), "=")
+	}
+
+	var buf bytes.Buffer
+	if err := s.codec.NewEncoder(&buf).Encode(session.Values); err != nil {
+		return err
+	}
+	s.cache.PutWithTTL(s.prefix+session.ID, buf.Bytes(), time.Duration(session.Options.MaxAge)*time.Second)
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
@@ -0,0 +1,57 @@
+package sessionstore
+
+import (
+	"fmt"
+
+	"github.com/gorilla/sessions"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option func(*options)
+
+type options struct {
+	prefix   string
+	codec    cache.Codec
+	cookie   sessions.Options
+	keyPairs [][]byte
+}
+
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+func WithCodec(codec cache.Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+func WithCookieOptions(opts sessions.Options) Option {
+	return func(o *options) {
+		o.cookie = opts
+	}
+}
+
+func WithKeyPairs(keyPairs ...[]byte) Option {
+	return func(o *options) {
+		o.keyPairs = keyPairs
+	}
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{
+		prefix: "session:",
+		codec:  cache.GobCodec,
+		cookie: sessions.Options{Path: "/", MaxAge: 86400 * 30, HttpOnly: true},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.codec == nil {
+		return o, fmt.Errorf("%w: codec must not be nil", cache.ErrInvalidOption)
+	}
+	return o, nil
+}
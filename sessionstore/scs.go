@@ -0,0 +1,66 @@
+package sessionstore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type ttlStore interface {
+	Get(k string) ([]byte, bool)
+	PutWithTTL(k string, v []byte, d time.Duration)
+	Delete(k string) bool
+	Keys() []string
+}
+
+type scsStore struct {
+	cache  ttlStore
+	prefix string
+}
+
+func NewSCSStore(c ttlStore, opts ...Option) (*scsStore, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &scsStore{cache: c, prefix: o.prefix}, nil
+}
+
+func (s *scsStore) Find(token string) ([]byte, bool, error) {
+	b, ok := s.cache.Get(s.prefix + token)
+	return b, ok, nil
+}
+
+func (s *scsStore) Commit(token string, b []byte, expiry time.Time) error {
+	d := time.Until(expiry)
+	if d <= 0 {
+		s.cache.Delete(s.prefix + token)
+		return nil
+	}
+	s.cache.PutWithTTL(s.prefix+token, b, d)
+	return nil
+}
+
+func (s *scsStore) Delete(token string) error {
+	s.cache.Delete(s.prefix + token)
+	return nil
+}
+
+func (s *scsStore) All() (map[string][]byte, error) {
+	all := make(map[string][]byte)
+	for _, k := range s.cache.Keys() {
+		token, ok := strings.CutPrefix(k, s.prefix)
+		if !ok {
+			continue
+		}
+		if b, ok := s.cache.Get(k); ok {
+			all[token] = b
+		}
+	}
+	return all, nil
+}
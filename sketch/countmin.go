@@ -0,0 +1,89 @@
+package sketch
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	depth      = 4
+	maxCount   = 15
+	resetMask  = 0x7777777777777777
+	perCounter = 4
+	perWord    = 64 / perCounter
+)
+
+type countMin[Key comparable] struct {
+	rows       [depth][]uint64
+	mask       uint64
+	additions  int
+	sampleSize int
+	seed       maphash.Seed
+}
+
+func New[Key comparable](width int) (*countMin[Key], error) {
+	if width <= 0 {
+		return nil, fmt.Errorf("width must be greater than zero")
+	}
+	width = 1 << bits.Len(uint(width-1))
+	s := &countMin[Key]{
+		mask:       uint64(width - 1),
+		sampleSize: 10 * width,
+		seed:       maphash.MakeSeed(),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint64, (width+perWord-1)/perWord)
+	}
+	return s, nil
+}
+
+func (s *countMin[Key]) Increment(k Key) {
+	h := maphash.Comparable(s.seed, k)
+	added := false
+	for i := range s.rows {
+		idx := s.index(h, i)
+		word, shift := idx/perWord, (idx%perWord)*perCounter
+		if (s.rows[i][word]>>shift)&maxCount < maxCount {
+			s.rows[i][word] += 1 << shift
+			added = true
+		}
+	}
+	if added {
+		s.additions++
+		if s.additions >= s.sampleSize {
+			s.Reset()
+		}
+	}
+}
+
+func (s *countMin[Key]) Estimate(k Key) uint8 {
+	h := maphash.Comparable(s.seed, k)
+	est := uint64(maxCount)
+	for i := range s.rows {
+		idx := s.index(h, i)
+		est = min(est, (s.rows[i][idx/perWord]>>((idx%perWord)*perCounter))&maxCount)
+	}
+	return uint8(est)
+}
+
+func (s *countMin[Key]) Reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] = (s.rows[i][j] >> 1) & resetMask
+		}
+	}
+	s.additions /= 2
+}
+
+func (s *countMin[Key]) Clear() {
+	for i := range s.rows {
+		clear(s.rows[i])
+	}
+	s.additions = 0
+}
+
+func (s *countMin[Key]) index(h uint64, row int) uint64 {
+	h1, h2 := h, h>>32|h<<32
+	return (h1 + uint64(row)*h2*0x9e3779b97f4a7c15) & s.mask
+}
@@ -0,0 +1,236 @@
+package sqlcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/assaidy/caches/cache"
+)
+
+const queryTimeout = 30 * time.Second
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+type ttlPutter interface {
+	PutWithTTL(k string, v []byte, d time.Duration)
+}
+
+type keyLister interface {
+	Keys() []string
+}
+
+type db struct {
+	db      *sql.DB
+	cache   cache.Cache[string, []byte]
+	ttl     time.Duration
+	codec   cache.Codec
+	hooks   []ExecHook
+	logger  *slog.Logger
+	flights singleflight.Group
+	gen     atomic.Uint64
+}
+
+func New(sqlDB *sql.DB, c cache.Cache[string, []byte], opts ...Option) (*db, error) {
+	if sqlDB == nil {
+		return nil, fmt.Errorf("%w: db must not be nil", cache.ErrInvalidOption)
+	}
+	if c == nil {
+		return nil, fmt.Errorf("%w: cache must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := c.(ttlPutter); o.ttl > 0 && !ok {
+		return nil, fmt.Errorf("%w: cache does not support per-entry ttl", cache.ErrInvalidOption)
+	}
+	return &db{
+		db:     sqlDB,
+		cache:  c,
+		ttl:    o.ttl,
+		codec:  o.codec,
+		hooks:  o.hooks,
+		logger: o.logger,
+	}, nil
+}
+
+func (d *db) DB() *sql.DB {
+	return d.db
+}
+
+func QueryContext[T any](ctx context.Context, d *db, scan func(*sql.Rows) (T, error), query string, args ...any) ([]T, error) {
+	key := queryKey(query, args)
+	var out []T
+	if data, ok := d.cache.Get(key); ok {
+		err := d.codec.NewDecoder(bytes.NewReader(data)).Decode(&out)
+		if err == nil {
+			return out, nil
+		}
+		d.logger.Debug("sql cache decode failed", "query", query, "err", err)
+		d.cache.Delete(key)
+	}
+
+	ch := d.flights.DoChan(fmt.Sprintf("%T\x00%s", out, key), func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), queryTimeout)
+		defer cancel()
+
+		gen := d.gen.Load()
+		rows, err := d.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var out []T
+		for rows.Next() {
+			row, err := scan(rows)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, row)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		d.store(key, out, gen)
+		return out, nil
+	})
+	select {
+	case r := <-ch:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return slices.Clone(r.Val.([]T)), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (d *db) QueryMaps(ctx context.Context, query string, args ...any) ([]map[string]any, error) {
+	return QueryContext(ctx, d, ScanMap, query, args...)
+}
+
+func ScanMap(rows *sql.Rows) (map[string]any, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	m := make(map[string]any, len(cols))
+	for i, col := range cols {
+		if b, ok := vals[i].([]byte); ok {
+			vals[i] = bytes.Clone(b)
+		}
+		m[col] = vals[i]
+	}
+	return m, nil
+}
+
+func (d *db) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := d.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, fn := range d.hooks {
+		for _, q := range fn(query, args) {
+			d.InvalidateQuery(q)
+		}
+	}
+	return res, nil
+}
+
+func (d *db) Invalidate(query string, args ...any) {
+	d.gen.Add(1)
+	d.cache.Delete(queryKey(query, args))
+}
+
+func (d *db) InvalidateQuery(query string) {
+	d.gen.Add(1)
+	lister, ok := d.cache.(keyLister)
+	if !ok {
+		d.cache.Clear()
+		return
+	}
+	prefix := queryPrefix(query)
+	for _, k := range lister.Keys() {
+		if strings.HasPrefix(k, prefix) {
+			d.cache.Delete(k)
+		}
+	}
+}
+
+func (d *db) store(key string, rows any, gen uint64) {
+	if d.gen.Load() != gen {
+		return
+	}
+	var buf bytes.Buffer
+	if err := d.codec.NewEncoder(&buf).Encode(rows); err != nil {
+		d.logger.Debug("sql cache encode failed", "key", key, "err", err)
+		return
+	}
+	if d.ttl > 0 {
+		d.cache.(ttlPutter).PutWithTTL(key, buf.Bytes(), d.ttl)
+	} else {
+		d.cache.Put(key, buf.Bytes())
+	}
+	if d.gen.Load() != gen {
+		d.cache.Delete(key)
+	}
+}
+
+func queryPrefix(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return "sql:" + hex.EncodeToString(sum[:8]) + ":"
+}
+
+func queryKey(query string, args []any) string {
+	h := sha256.New()
+	for _, a := range args {
+		if v, ok := a.(sql.NamedArg); ok {
+			fmt.Fprintf(h, "%s=", v.Name)
+			a = v.Value
+		}
+		a = deref(a)
+		if t, ok := a.(time.Time); ok {
+			fmt.Fprintf(h, "%T:%s\x00", t, t.UTC().Format(time.RFC3339Nano))
+			continue
+		}
+		fmt.Fprintf(h, "%T:%v\x00", a, a)
+	}
+	return queryPrefix(query) + hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func deref(a any) any {
+	rv := reflect.ValueOf(a)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
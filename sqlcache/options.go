@@ -0,0 +1,66 @@
+package sqlcache
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type ExecHook func(query string, args []any) []string
+
+type Option func(*options)
+
+type options struct {
+	ttl    time.Duration
+	codec  cache.Codec
+	hooks  []ExecHook
+	logger *slog.Logger
+}
+
+func WithTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.ttl = d
+	}
+}
+
+func WithCodec(codec cache.Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+func WithExecHook(fn ExecHook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, fn)
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{
+		codec:  cache.GobCodec,
+		logger: slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ttl < 0 {
+		return o, cache.ErrInvalidTTL
+	}
+	if o.codec == nil {
+		return o, fmt.Errorf("%w: codec must not be nil", cache.ErrInvalidOption)
+	}
+	for _, fn := range o.hooks {
+		if fn == nil {
+			return o, fmt.Errorf("%w: exec hook must not be nil", cache.ErrInvalidOption)
+		}
+	}
+	return o, nil
+}
@@ -0,0 +1,115 @@
+package tokencache
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/assaidy/caches/cache"
+)
+
+const loadTimeout = 30 * time.Second
+
+type Fetcher func(ctx context.Context, key string) (Token, error)
+
+type ttlStore interface {
+	GetWithExpiry(k string) (Token, time.Time, bool)
+	PutWithTTL(k string, v Token, d time.Duration)
+	Delete(k string) bool
+}
+
+type tokenCache struct {
+	fetch         Fetcher
+	tokens        ttlStore
+	margin        time.Duration
+	refreshBefore time.Duration
+	clock         cache.Clock
+	logger        *slog.Logger
+	flights       singleflight.Group
+	refreshing    sync.Map
+}
+
+func New(fetch Fetcher, opts ...Option) (*tokenCache, error) {
+	if fetch == nil {
+		return nil, fmt.Errorf("%w: fetcher must not be nil", cache.ErrInvalidOption)
+	}
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	copts := []cache.Option[string, Token]{cache.WithClock[string, Token](o.clock)}
+	if o.capacity > 0 {
+		copts = append(copts, cache.WithCapacity[string, Token](o.capacity))
+	}
+	tokens, err := cache.NewTTL(time.Hour, false, copts...)
+	if err != nil {
+		return nil, err
+	}
+	return &tokenCache{
+		fetch:         fetch,
+		tokens:        tokens,
+		margin:        o.margin,
+		refreshBefore: o.refreshBefore,
+		clock:         o.clock,
+		logger:        o.logger,
+	}, nil
+}
+
+func (c *tokenCache) Token(ctx context.Context, key string) (Token, error) {
+	if tok, exp, ok := c.tokens.GetWithExpiry(key); ok {
+		if exp.Sub(c.clock.Now()) < c.refreshBefore {
+			if _, busy := c.refreshing.LoadOrStore(key, struct{}{}); !busy {
+				go c.refresh(ctx, key)
+			}
+		}
+		return tok, nil
+	}
+	select {
+	case r := <-c.shared(ctx, key):
+		if r.Err != nil {
+			return Token{}, r.Err
+		}
+		return r.Val.(Token), nil
+	case <-ctx.Done():
+		return Token{}, ctx.Err()
+	}
+}
+
+func (c *tokenCache) Invalidate(key string) {
+	c.tokens.Delete(key)
+}
+
+func (c *tokenCache) refresh(ctx context.Context, key string) {
+	defer c.refreshing.Delete(key)
+
+	if r := <-c.shared(ctx, key); r.Err != nil {
+		c.logger.Warn("token refresh failed", "key", key, "err", r.Err)
+	}
+}
+
+func (c *tokenCache) shared(ctx context.Context, key string) <-chan singleflight.Result {
+	return c.flights.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+		return c.load(ctx, key)
+	})
+}
+
+func (c *tokenCache) load(ctx context.Context, key string) (Token, error) {
+	tok, err := c.fetch(ctx, key)
+	if err != nil {
+		return Token{}, err
+	}
+	exp, err := tok.expiry()
+	if err != nil {
+		return Token{}, err
+	}
+	if ttl := exp.Sub(c.clock.Now()) - c.margin; ttl > 0 {
+		c.tokens.PutWithTTL(key, tok, ttl)
+	}
+	return tok, nil
+}
@@ -0,0 +1,71 @@
+package tokencache
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/assaidy/caches/cache"
+)
+
+type Option func(*options)
+
+type options struct {
+	margin        time.Duration
+	refreshBefore time.Duration
+	capacity      int
+	clock         cache.Clock
+	logger        *slog.Logger
+}
+
+func WithMargin(d time.Duration) Option {
+	return func(o *options) {
+		o.margin = d
+	}
+}
+
+func WithRefreshBefore(d time.Duration) Option {
+	return func(o *options) {
+		o.refreshBefore = d
+	}
+}
+
+func WithCapacity(n int) Option {
+	return func(o *options) {
+		o.capacity = n
+	}
+}
+
+func WithClock(clock cache.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func applyOptions(opts []Option) (options, error) {
+	o := options{
+		margin:        30 * time.Second,
+		refreshBefore: time.Minute,
+		clock:         cache.SystemClock,
+		logger:        slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.margin < 0 || o.refreshBefore < 0 {
+		return o, fmt.Errorf("%w: margin and refresh window must not be negative", cache.ErrInvalidOption)
+	}
+	if o.capacity < 0 {
+		return o, cache.ErrInvalidCapacity
+	}
+	if o.clock == nil {
+		return o, fmt.Errorf("%w: clock must not be nil", cache.ErrInvalidOption)
+	}
+	return o, nil
+}
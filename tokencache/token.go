@@ -0,0 +1,50 @@
+package tokencache
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrNoExpiry = errors.New("token has no expiry")
+
+type Token struct {
+	AccessToken string
+	TokenType   string
+	ExpiresAt   time.Time
+}
+
+func (t Token) expiry() (time.Time, error) {
+	if !t.ExpiresAt.IsZero() {
+		return t.ExpiresAt, nil
+	}
+	return JWTExpiry(t.AccessToken)
+}
+
+func JWTExpiry(raw string) (time.Time, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return time.Time{}, ErrNoExpiry
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, err
+	}
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, err
+	}
+	if claims.Exp == nil {
+		return time.Time{}, ErrNoExpiry
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec := int64(exp)
+	return time.Unix(sec, int64((exp-float64(sec))*1e9)), nil
+}